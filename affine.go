@@ -0,0 +1,53 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "math"
+
+// Transform is the combined movement of two fingers since they touched the screen, as the scale,
+// rotation and translation of an affine transform, like to place a sticker with two fingers.
+type Transform struct {
+	// Scale is the ratio between the current and the initial distance between the fingers.
+	Scale float64
+	// Rotation is how much the fingers rotated around each other, in radians within (-π, π].
+	// Positive values are clockwise on screen.
+	Rotation float64
+	// TranslateX, TranslateY is the movement of the midpoint between the fingers.
+	TranslateX, TranslateY int
+}
+
+// Transform returns the combined Transform of the two fingers touching the screen, as of the last
+// update frame, whether they are recognized as a pinch, a pan or neither.
+//
+// It's a single model for apps that scale, rotate and move at once, while Pinch and TwoFingerPan
+// tell those gestures apart: Scale is like Pinch.Scale, Rotation like Pinch.RotationDelta, and the
+// translation like the movement of TwoFingerPan from its origin, but all of them are reported from
+// the first frame both fingers touch the screen. With more fingers, it follows the fingers of the
+// pinch or pan in progress, if any.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Transform() (Transform, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if len(tt.touchIDs) < 2 || (len(tt.touchIDs) > 2 && tt.pinch == nil && tt.pan == nil) {
+		return Transform{}, false
+	}
+	id1, id2 := tt.anchors()
+	t1, t2 := tt.touches[id1], tt.touches[id2]
+	if t1 == nil || t2 == nil || t1.consumed || t2.consumed {
+		return Transform{}, false
+	}
+
+	tr := Transform{Scale: 1}
+	if originDiff := distance2d(t1.originX, t1.originY, t2.originX, t2.originY); originDiff > 0 {
+		tr.Scale = distance2d(t1.currX, t1.currY, t2.currX, t2.currY) / originDiff
+	}
+	originAngle := math.Atan2(float64(t2.originY-t1.originY), float64(t2.originX-t1.originX))
+	angle := math.Atan2(float64(t2.currY-t1.currY), float64(t2.currX-t1.currX))
+	tr.Rotation = normalizeAngle(angle - originAngle)
+
+	midX, midY := (t1.currX+t2.currX)/2, (t1.currY+t2.currY)/2
+	dx, dy := midX-(t1.originX+t2.originX)/2, midY-(t1.originY+t2.originY)/2
+	tr.TranslateX, tr.TranslateY = tt.toWorldDelta(midX, midY, dx, dy)
+	return tr, true
+}
@@ -0,0 +1,34 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// ambiguity scores how close two competing pieces of gesture evidence are.
+//
+// Evidence is expressed as a fraction of the threshold needed to recognize each gesture,
+// so 1 means the gesture is about to be recognized. The score is the ratio between the
+// weaker and the stronger evidence, scaled by how close the stronger one is to its threshold.
+func ambiguity(a, b float64) float64 {
+	hi, lo := max(a, b), min(a, b)
+	if hi <= 0 {
+		return 0
+	}
+	return lo / hi * min(hi, 1)
+}
+
+// AmbiguityScore returns how ambiguous the input was between competing gestures in the last
+// update frame, from 0 (clearly one gesture, or none at all) to 1 (totally ambiguous).
+//
+// It's computed while two fingers are touching the screen and neither a pinch nor a pan has been
+// recognized yet. The pinch evidence is the change in distance between the fingers and the pan
+// evidence is the movement of the midpoint between them, both relative to the thresholds used to recognize
+// each gesture. The score is the ratio between the weaker and the stronger evidence, scaled down
+// while the stronger one is still far from its threshold. Once a gesture is recognized the score is 0.
+//
+// This is meant as a diagnostic metric, to show disambiguation hints or defer actions.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) AmbiguityScore() float64 {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return tt.ambiguity
+}
@@ -0,0 +1,47 @@
+package ebiten_touchutils
+
+// Camera is a 2D camera with a translation and a zoom, as used to zoom a world with a pinch.
+//
+// X, Y is the world position shown at the top-left corner of the screen, and Zoom is how many
+// screen pixels a world unit spans, so a world point (wx, wy) is drawn at screen position
+// ((wx-X)*Zoom, (wy-Y)*Zoom). A zero Zoom is taken as 1, so the zero Camera shows the world
+// unscaled from its origin.
+type Camera struct {
+	X, Y float64
+	Zoom float64
+}
+
+// zoom returns the zoom of the camera, taking a zero Zoom as 1.
+func (c Camera) zoom() float64 {
+	if c.Zoom == 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// ScreenToWorld returns the world position shown at screen position (sx, sy).
+func (c Camera) ScreenToWorld(sx, sy float64) (float64, float64) {
+	zoom := c.zoom()
+	return sx/zoom + c.X, sy/zoom + c.Y
+}
+
+// WorldToScreen returns the screen position where world position (wx, wy) is drawn.
+func (c Camera) WorldToScreen(wx, wy float64) (float64, float64) {
+	zoom := c.zoom()
+	return (wx - c.X) * zoom, (wy - c.Y) * zoom
+}
+
+// ApplyPinchZoom returns cam zoomed by the change in distance between the fingers of p since the
+// previous update frame, keeping the world point under the pinch center pinned under the fingers.
+// Call it on every update frame a pinch is being made.
+//
+// The pinch center must be in screen coordinates, so this is meant for trackers without a
+// coordinate transform.
+func ApplyPinchZoom(cam Camera, p Pinch) Camera {
+	cx, cy := float64(p.CenterX), float64(p.CenterY)
+	wx, wy := cam.ScreenToWorld(cx, cy)
+	cam.Zoom = cam.zoom() * p.ScaleDelta()
+	cam.X = wx - cx/cam.Zoom
+	cam.Y = wy - cy/cam.Zoom
+	return cam
+}
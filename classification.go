@@ -0,0 +1,63 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Classification is what the tracker considers an active touch to be part of.
+type Classification int
+
+const (
+	// ClassUnknown touches take part in no gesture and can no longer be released as a tap.
+	ClassUnknown Classification = iota
+	// ClassTap touches take part in no gesture yet, and would be a tap if released now.
+	ClassTap
+	ClassPan
+	ClassPinch
+	ClassThreeFingerPan
+	ClassDrag
+	ClassLongPress
+	// ClassIgnored touches are excluded from gesture recognition until released, like after
+	// CancelGesture or while paused with PauseDrop.
+	ClassIgnored
+)
+
+// classify returns the classification of touch t.
+func (tt *TouchTracker) classify(t *touch) Classification {
+	switch {
+	case t.consumed:
+		return ClassIgnored
+	case t.gestures.has(GesturePinch):
+		return ClassPinch
+	case t.gestures.has(GesturePan):
+		return ClassPan
+	case t.gestures.has(GestureThreeFingerPan):
+		return ClassThreeFingerPan
+	case t.gestures.has(GestureDrag), t.gestures.has(GestureTapDrag):
+		return ClassDrag
+	case t.gestures.has(GestureLongPress):
+		return ClassLongPress
+	case !tt.suppressed(GestureTap, t) && tt.isTapDuration(t) &&
+		distance2d(t.originX, t.originY, t.currX, t.currY) < tt.tapMaxMovement:
+		return ClassTap
+	default:
+		return ClassUnknown
+	}
+}
+
+// TouchClassification returns what the active touch with the given id takes part in, as of the last
+// update frame, to coordinate custom gestures with the ones recognized by the tracker.
+//
+// A touch that took part in a gesture keeps that classification until released, even after
+// the gesture ends.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TouchClassification(id ebiten.TouchID) (Classification, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	t, ok := tt.touches[id]
+	if !ok {
+		return ClassUnknown, false
+	}
+	return tt.classify(t), true
+}
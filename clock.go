@@ -0,0 +1,87 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "time"
+
+// WithTimeSource sets the clock used to measure how long touches are held, when thresholds
+// are set as durations with WithTapMaxTime or WithLongPressTime, and velocities in pixels per second.
+// Defaults to time.Now.
+//
+// Injecting a fake clock makes time based thresholds deterministic in tests.
+func WithTimeSource(now func() time.Time) Option {
+	return func(tt *TouchTracker) {
+		tt.now = now
+	}
+}
+
+// WithTapMaxTime sets for how long a finger can be touching the screen to be recorded as a tap
+// when released, measured in wall-clock time rather than frames. This keeps the tap window right
+// when the game runs at a variable TPS.
+//
+// When set, it takes precedence over WithTapMaxDuration. Defaults to 0, which counts frames instead.
+func WithTapMaxTime(d time.Duration) Option {
+	return func(tt *TouchTracker) {
+		tt.tapMaxTime = d
+	}
+}
+
+// WithLongPressTime sets for how long a finger must be held down to be a long press,
+// measured in wall-clock time rather than frames.
+//
+// When set, it takes precedence over WithLongPressDuration. Defaults to 0, which counts frames instead.
+func WithLongPressTime(d time.Duration) Option {
+	return func(tt *TouchTracker) {
+		tt.longPressTime = d
+	}
+}
+
+// heldFor returns for how long touch t has been held down, as of the current update frame.
+func (tt *TouchTracker) heldFor(t *touch) time.Duration {
+	return tt.clock.Sub(t.pressedAt)
+}
+
+// isTapDuration returns if touch t has been held down briefly enough to be a tap,
+// including the gap before a long press with TapGapTap.
+func (tt *TouchTracker) isTapDuration(t *touch) bool {
+	if tt.tapLongPressGap == TapGapTap && !tt.isLongPressDuration(t) {
+		return true
+	}
+	if tt.tapMaxTime > 0 {
+		return tt.heldFor(t) <= tt.tapMaxTime
+	}
+	return t.duration <= tt.tapMaxDuration
+}
+
+// isLongPressDuration returns if touch t has been held down long enough to be a long press.
+func (tt *TouchTracker) isLongPressDuration(t *touch) bool {
+	if tt.longPressTime > 0 {
+		return tt.heldFor(t) >= tt.longPressTime
+	}
+	return t.duration >= tt.longPressDuration
+}
+
+// frameSeconds returns how long the last update frame lasted by the tracker clock, in seconds.
+func (tt *TouchTracker) frameSeconds() float64 {
+	if tt.frameTime <= 0 {
+		return defaultFrameTime.Seconds()
+	}
+	return tt.frameTime.Seconds()
+}
+
+// perSecond converts a velocity in pixels per frame into pixels per second, by the duration
+// of the last update frame.
+func (tt *TouchTracker) perSecond(vx, vy float64) (float64, float64) {
+	secs := tt.frameSeconds()
+	return vx / secs, vy / secs
+}
+
+// averagePerSecond returns the average velocity, in pixels per second, of touch t moving by (dx, dy)
+// since it was pressed, by the time it has been held down.
+func (tt *TouchTracker) averagePerSecond(t *touch, dx, dy float64) (float64, float64) {
+	secs := tt.heldFor(t).Seconds()
+	if secs <= 0 {
+		secs = float64(max(t.duration, 1)) * tt.frameSeconds()
+	}
+	return dx / secs, dy / secs
+}
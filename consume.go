@@ -0,0 +1,70 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// Consume marks the gesture of the given kind made in the last update frame as handled, so its
+// accessors report nothing more until the next Update. This lets layered input handlers, like
+// the UI and then the game world, poll the same tracker with a first-come priority.
+//
+// Consuming GestureTap hides every kind of tap, including double, triple, split and multi finger ones.
+// Consuming GesturePan and GesturePinch also hides PanJustEnded and PinchJustEnded.
+// Consuming GestureRotate keeps the pinch reported, but not as rotating.
+// Gestures in progress are reported again on the next update frame, unless consumed again.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Consume(kind GestureKind) {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	tt.handled.add(kind)
+}
+
+// ConsumeTap marks the taps made in the last update frame as handled. See Consume.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ConsumeTap() {
+	tt.Consume(GestureTap)
+}
+
+// ConsumePan marks the two finger pan of the last update frame as handled. See Consume.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ConsumePan() {
+	tt.Consume(GesturePan)
+}
+
+// ConsumePinch marks the pinch of the last update frame as handled. See Consume.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ConsumePinch() {
+	tt.Consume(GesturePinch)
+}
+
+// ConsumeDrag marks the drag of the last update frame as handled. See Consume.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ConsumeDrag() {
+	tt.Consume(GestureDrag)
+}
+
+// ConsumePanDelta returns the movement of the two finger pan since the previous call, or since the
+// frame before it was recognized on the first call, and marks it as read. It's meant for game logic
+// that polls the pan less often than every update frame, where FrameDelta would miss movement.
+//
+// It reports the movement up to the frame the pan ends if called on that frame, and zero while no pan
+// is being made. Unlike ConsumePan, it doesn't hide the pan from other accessors.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ConsumePanDelta() (int, int) {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	p := tt.pan
+	if p == nil {
+		p = tt.panEnded
+	}
+	if p == nil || tt.handled.has(GesturePan) {
+		return 0, 0
+	}
+	dx, dy := p.LastX-p.polledX, p.LastY-p.polledY
+	p.polledX, p.polledY = p.LastX, p.LastY
+	return tt.toWorldDelta(p.LastX, p.LastY, dx, dy)
+}
@@ -95,14 +95,12 @@ func (g *Gesture) Draw(screen *ebiten.Image) {
 	} else if g.touch.IsTouchingTwo() {
 		msgs = append(msgs, "touching two")
 		if pan, ok := g.touch.TwoFingerPan(); ok {
+			if dir := pan.Direction(); dir != touchutils.DirNone {
+				msgs = append(msgs, fmt.Sprintf("swipe %s - delta: %d, %d", dir, pan.LastX-pan.OriginX, pan.LastY-pan.OriginY))
+			}
+
 			if pan.IsHorizontal() {
 				msgs = append(msgs, "horizontal pan")
-				deltaX := pan.OriginX - pan.LastX
-				if deltaX < -10 {
-					msgs = append(msgs, fmt.Sprintf("swipe right - delta: %d", deltaX))
-				} else if deltaX > 10 {
-					msgs = append(msgs, fmt.Sprintf("swipe left - delta: %d", deltaX))
-				}
 
 				vector.DrawFilledCircle(screen, float32(pan.OriginX), float32(g.h)/2, 5, color.RGBA{255, 0, 0, 1}, true)
 				vector.DrawFilledCircle(screen, float32(pan.LastX), float32(g.h)/2, 5, color.RGBA{0, 255, 0, 1}, true)
@@ -111,12 +109,6 @@ func (g *Gesture) Draw(screen *ebiten.Image) {
 
 			if pan.IsVertical() {
 				msgs = append(msgs, "vertical pan")
-				deltaY := pan.OriginY - pan.LastY
-				if deltaY < -10 {
-					msgs = append(msgs, fmt.Sprintf("swipe up - delta: %d", deltaY))
-				} else if deltaY > 10 {
-					msgs = append(msgs, fmt.Sprintf("swipe down - delta: %d", deltaY))
-				}
 
 				vector.DrawFilledCircle(screen, float32(g.w)/2, float32(pan.OriginY), 5, color.RGBA{255, 0, 0, 1}, true)
 				vector.DrawFilledCircle(screen, float32(g.w)/2, float32(pan.LastY), 5, color.RGBA{0, 255, 0, 1}, true)
@@ -131,10 +123,17 @@ func (g *Gesture) Draw(screen *ebiten.Image) {
 			if pinch.IsOutward() {
 				msgs = append(msgs, "outward pinch")
 			}
+			if pinch.IsHorizontal() {
+				msgs = append(msgs, "horizontal pinch")
+			} else if pinch.IsVertical() {
+				msgs = append(msgs, "vertical pinch")
+			}
 
-			vector.DrawFilledCircle(screen, float32(pinch.CenterX)-float32(pinch.Distance/2), float32(pinch.CenterY), 5, color.RGBA{255, 0, 0, 1}, true)
-			vector.DrawFilledCircle(screen, float32(pinch.CenterX)+float32(pinch.Distance/2), float32(pinch.CenterY), 5, color.RGBA{0, 255, 0, 1}, true)
-			vector.StrokeLine(screen, float32(pinch.CenterX)-float32(pinch.Distance/2), float32(pinch.CenterY), float32(pinch.CenterX)+float32(pinch.Distance/2), float32(pinch.CenterY), 1, color.White, true)
+			x1, y1 := pinch.Finger1()
+			x2, y2 := pinch.Finger2()
+			vector.DrawFilledCircle(screen, float32(x1), float32(y1), 5, color.RGBA{255, 0, 0, 1}, true)
+			vector.DrawFilledCircle(screen, float32(x2), float32(y2), 5, color.RGBA{0, 255, 0, 1}, true)
+			vector.StrokeLine(screen, float32(x1), float32(y1), float32(x2), float32(y2), 1, color.White, true)
 		}
 	} else if g.touch.IsTouching() {
 		x, y, _ := g.touch.GetFirstTouchPosition()
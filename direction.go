@@ -0,0 +1,46 @@
+package ebiten_touchutils
+
+import "math"
+
+// Direction is the dominant direction of a movement across the screen.
+type Direction int
+
+const (
+	DirNone Direction = iota
+	DirUp
+	DirDown
+	DirLeft
+	DirRight
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirUp:
+		return "up"
+	case DirDown:
+		return "down"
+	case DirLeft:
+		return "left"
+	case DirRight:
+		return "right"
+	default:
+		return "none"
+	}
+}
+
+// directionOf returns the direction of the axis with the larger movement.
+// Screen coordinates grow downwards, so a negative dy moves up.
+func directionOf(dx, dy float64) Direction {
+	switch {
+	case dx == 0 && dy == 0:
+		return DirNone
+	case math.Abs(dx) >= math.Abs(dy) && dx > 0:
+		return DirRight
+	case math.Abs(dx) >= math.Abs(dy):
+		return DirLeft
+	case dy > 0:
+		return DirDown
+	default:
+		return DirUp
+	}
+}
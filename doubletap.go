@@ -0,0 +1,114 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// WithDoubleTapWindow sets the maximum amount of frames between two taps for them to be a double tap.
+// Defaults to 20.
+func WithDoubleTapWindow(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.doubleTapWindow = frames
+	}
+}
+
+// WithDoubleTapRadius sets the maximum distance, in pixels, between two taps for them to be a double tap.
+// Defaults to 20.
+func WithDoubleTapRadius(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.doubleTapRadius = px
+	}
+}
+
+// updateDoubleTap chains the one finger taps of the last frame with the previous ones into double
+// and triple taps, and confirms single taps once they can no longer become double taps.
+func (tt *TouchTracker) updateDoubleTap() {
+	tt.doubleTap = nil
+	tt.tripleTap = nil
+	tt.singleTap = nil
+
+	// The chain ends when the gap since its last tap exceeds the window, or a multi finger tap is made.
+	if n := len(tt.tapChain); n > 0 && (len(tt.taps) > 1 || tt.frame-tt.tapChain[n-1].frame > tt.doubleTapWindow) {
+		tt.endTapChain()
+	}
+	if len(tt.taps) != 1 {
+		return
+	}
+
+	tap := tt.taps[0]
+	if len(tt.tapChain) > 0 {
+		first := tt.tapChain[0]
+		if distance2d(first.X, first.Y, tap.X, tap.Y) > tt.doubleTapRadius {
+			tt.endTapChain()
+		}
+	}
+	tt.tapChain = append(tt.tapChain, tap)
+
+	// The second and third taps are reported as a double or triple tap only, not as another single one.
+	switch len(tt.tapChain) {
+	case 2:
+		tt.doubleTap = &tap.Tap
+		tt.taps = tt.taps[:0]
+	case 3:
+		tt.tripleTap = &tap.Tap
+		tt.taps = tt.taps[:0]
+		tt.tapChain = tt.tapChain[:0]
+	}
+}
+
+// endTapChain drops the taps chained so far, confirming the first one as a single tap if it
+// was left alone.
+func (tt *TouchTracker) endTapChain() {
+	if len(tt.tapChain) == 1 {
+		tt.singleTap = &tt.tapChain[0].Tap
+	}
+	tt.tapChain = nil
+}
+
+// DoubleTapped returns Tap coordinates of the second tap if a double tap was made (released) in the last update frame.
+//
+// Two one finger taps are a double tap if made within the double tap window and radius.
+// The second tap is not reported by TappedOne, but the first one is, as it can't be known yet
+// whether another tap will follow. Use SingleTapped to only handle taps that didn't become double taps.
+//
+// A third tap in the same area within the window after a double tap is reported by TripleTapped only.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) DoubleTapped() (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.doubleTap != nil && !tt.handled.has(GestureTap) {
+		return tt.worldTap(*tt.doubleTap), true
+	}
+	return Tap{}, false
+}
+
+// SingleTapped returns Tap coordinates of a one finger tap once it can no longer become a double tap,
+// which is when the double tap window elapses or another tap is made elsewhere.
+//
+// Unlike TappedOne, this is reported some frames after the tap is made, but never for a tap that
+// ends up being part of a double tap.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) SingleTapped() (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.singleTap != nil && !tt.handled.has(GestureTap) {
+		return tt.worldTap(*tt.singleTap), true
+	}
+	return Tap{}, false
+}
+
+// TripleTapped returns Tap coordinates of the third tap if a triple tap was made (released) in the last update frame.
+//
+// Three one finger taps are a triple tap if each one follows the previous one within the double tap window,
+// and all of them are within the double tap radius of the first one. Only one of TappedOne, DoubleTapped and
+// TripleTapped reports each tap of the chain: the first, second and third one respectively.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TripleTapped() (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.tripleTap != nil && !tt.handled.has(GestureTap) {
+		return tt.worldTap(*tt.tripleTap), true
+	}
+	return Tap{}, false
+}
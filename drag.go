@@ -0,0 +1,117 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// WithDragThreshold sets how far, in pixels, a single finger must move from where it was pressed
+// to start a drag. Defaults to 10.
+func WithDragThreshold(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.dragThreshold = px
+	}
+}
+
+// updateDrag starts a drag when a single finger moves far enough, and follows it until released.
+func (tt *TouchTracker) updateDrag() {
+	if !tt.dragging && len(tt.touchIDs) == 1 {
+		id := tt.touchIDs[0]
+		t := tt.touches[id]
+		kind := GestureDrag
+		if t.afterTap != nil {
+			kind = GestureTapDrag
+		}
+		moved := distance2d(t.originX, t.originY, t.currX, t.currY)
+		if !t.consumed && moved > tt.dragThreshold && !tt.suppressed(kind, t) {
+			t.gestures.add(kind)
+			tt.record(kind, t.currX, t.currY)
+			tt.dragging = true
+			tt.dragID = id
+
+			// The tap before a tap drag is part of it, so it can't be a single tap nor start a double tap.
+			if kind == GestureTapDrag {
+				tt.tapChain = nil
+			}
+		}
+	}
+	if !tt.dragging {
+		return
+	}
+
+	t, ok := tt.touches[tt.dragID]
+	if !ok || t.consumed {
+		tt.dragging = false
+		return
+	}
+	d := tt.dragOf(t)
+	if t.gestures.has(GestureTapDrag) {
+		tt.frameGestures.tapDrag = TapDrag{Tap: *t.afterTap, Drag: d}
+		tt.tapDrag = &tt.frameGestures.tapDrag
+	} else {
+		tt.frameGestures.drag = d
+		tt.drag = &tt.frameGestures.drag
+	}
+}
+
+// updateTouchDrags marks the touches that moved past the drag threshold, for DragByID.
+func (tt *TouchTracker) updateTouchDrags() {
+	for _, t := range tt.touches {
+		if !t.dragged && !t.consumed && distance2d(t.originX, t.originY, t.currX, t.currY) > tt.dragThreshold {
+			t.dragged = true
+		}
+	}
+}
+
+// dragOf returns the Drag data of touch t as of the current frame.
+func (tt *TouchTracker) dragOf(t *touch) Drag {
+	vsx, vsy := tt.perSecond(t.vx, t.vy)
+	return Drag{
+		StartX:    t.originX,
+		StartY:    t.originY,
+		CurrX:     t.currX,
+		CurrY:     t.currY,
+		DeltaX:    t.currX - t.prevX,
+		DeltaY:    t.currY - t.prevY,
+		VelocityX: t.vx,
+		VelocityY: t.vy,
+
+		VelocityPerSecondX: vsx,
+		VelocityPerSecondY: vsy,
+	}
+}
+
+// Drag returns the latest Drag data if a one finger drag is being made.
+//
+// A drag starts when a single finger moves past the drag threshold, and lasts until that finger
+// is released, even if other fingers touch the screen meanwhile. A finger that made a drag is not
+// recorded as a tap when released.
+//
+// Drag data updates every update frame.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Drag() (Drag, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.drag != nil && !tt.handled.has(GestureDrag) {
+		return tt.worldDrag(*tt.drag), true
+	}
+	return Drag{}, false
+}
+
+// DragByID returns the latest Drag data of the touch with the given id if it moved past the drag
+// threshold, to follow fingers independently, like one per player in a local multiplayer game.
+//
+// Unlike Drag, it reports every finger moving past the threshold, regardless of other fingers
+// touching the screen or the multi finger gestures it takes part in, and doesn't affect the
+// recognition of other gestures. Touches excluded from gesture recognition are not reported.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) DragByID(id ebiten.TouchID) (Drag, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	t, ok := tt.touches[id]
+	if !ok || !t.dragged || t.consumed || tt.handled.has(GestureDrag) {
+		return Drag{}, false
+	}
+	return tt.worldDrag(tt.dragOf(t)), true
+}
@@ -0,0 +1,100 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// Edge is a border of the screen.
+type Edge int
+
+const (
+	EdgeNone Edge = iota
+	EdgeLeft
+	EdgeRight
+	EdgeTop
+	EdgeBottom
+)
+
+func (e Edge) String() string {
+	switch e {
+	case EdgeLeft:
+		return "left"
+	case EdgeRight:
+		return "right"
+	case EdgeTop:
+		return "top"
+	case EdgeBottom:
+		return "bottom"
+	default:
+		return "none"
+	}
+}
+
+// EdgeSwipe is a one finger swipe that started next to a border of the screen and moved away
+// from it, like the one that opens a navigation drawer.
+type EdgeSwipe struct {
+	Edge Edge
+
+	StartX, StartY int
+	EndX, EndY     int
+
+	// Distance is how far the finger traveled, in pixels.
+	Distance float64
+}
+
+// WithScreenSize sets the size of the screen in pixels, needed to recognize edge swipes.
+func WithScreenSize(w, h int) Option {
+	return func(tt *TouchTracker) {
+		tt.screenW, tt.screenH = w, h
+	}
+}
+
+// WithEdgeSwipeMargin sets how close to a border of the screen, in pixels, a swipe must start
+// to be an edge swipe. Defaults to 20.
+func WithEdgeSwipeMargin(px int) Option {
+	return func(tt *TouchTracker) {
+		tt.edgeSwipeMargin = px
+	}
+}
+
+// edgeOf returns the edge the swipe s started next to and moved away from, if any.
+func (tt *TouchTracker) edgeOf(s Swipe) Edge {
+	switch {
+	case s.Direction == DirRight && s.StartX < tt.edgeSwipeMargin:
+		return EdgeLeft
+	case s.Direction == DirLeft && s.StartX >= tt.screenW-tt.edgeSwipeMargin:
+		return EdgeRight
+	case s.Direction == DirDown && s.StartY < tt.edgeSwipeMargin:
+		return EdgeTop
+	case s.Direction == DirUp && s.StartY >= tt.screenH-tt.edgeSwipeMargin:
+		return EdgeBottom
+	default:
+		return EdgeNone
+	}
+}
+
+// EdgeSwipe returns the EdgeSwipe data if the one finger swipe made in the last update frame
+// started within the edge swipe margin of a border of the screen, and moved away from it.
+//
+// Always returns false unless the screen size is set with WithScreenSize.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) EdgeSwipe() (EdgeSwipe, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.swipe == nil || tt.handled.has(GestureSwipe) || tt.screenW <= 0 || tt.screenH <= 0 {
+		return EdgeSwipe{}, false
+	}
+	s := *tt.swipe
+	edge := tt.edgeOf(s)
+	if edge == EdgeNone {
+		return EdgeSwipe{}, false
+	}
+	w := tt.worldSwipe(s)
+	return EdgeSwipe{
+		Edge:     edge,
+		StartX:   w.StartX,
+		StartY:   w.StartY,
+		EndX:     w.EndX,
+		EndY:     w.EndY,
+		Distance: distance2d(s.StartX, s.StartY, s.EndX, s.EndY),
+	}, true
+}
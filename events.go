@@ -0,0 +1,210 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// handler is a callback registered on a TouchTracker.
+type handler[T any] struct {
+	id int
+	fn func(T)
+}
+
+// handlers is a list of callbacks, run in registration order.
+type handlers[T any] struct {
+	nextID int
+	list   []handler[T]
+}
+
+func (h *handlers[T]) add(fn func(T)) int {
+	h.nextID++
+	h.list = append(h.list, handler[T]{id: h.nextID, fn: fn})
+	return h.nextID
+}
+
+func (h *handlers[T]) remove(id int) {
+	for i, hd := range h.list {
+		if hd.id == id {
+			h.list = append(h.list[:i], h.list[i+1:]...)
+			return
+		}
+	}
+}
+
+func (h *handlers[T]) clear() {
+	h.list = nil
+}
+
+// snapshot returns a copy of the registered callbacks, so they can be run without holding
+// the tracker lock, and added or removed from within a callback.
+func (h *handlers[T]) snapshot() []func(T) {
+	fns := make([]func(T), len(h.list))
+	for i, hd := range h.list {
+		fns[i] = hd.fn
+	}
+	return fns
+}
+
+// on registers fn in h under the tracker lock, and returns a function removing it.
+func on[T any](tt *TouchTracker, h *handlers[T], fn func(T)) func() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	id := h.add(fn)
+	return func() {
+		tt.m.Lock()
+		defer tt.m.Unlock()
+		h.remove(id)
+	}
+}
+
+// OnTap registers fn to be called with every tap made (released), on the update frame it's made.
+// It returns a function that unregisters fn.
+//
+// Callbacks run at the end of Update in registration order, after the tracker is unlocked,
+// so they can safely query it.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnTap(fn func(Tap)) func() {
+	return on(tt, &tt.onTap, fn)
+}
+
+// OnDoubleTap registers fn to be called with the second tap of every double tap, on the update frame
+// it's made. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnDoubleTap(fn func(Tap)) func() {
+	return on(tt, &tt.onDoubleTap, fn)
+}
+
+// OnPinch registers fn to be called with the latest Pinch data on every update frame a pinch
+// gesture is being made. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnPinch(fn func(Pinch)) func() {
+	return on(tt, &tt.onPinch, fn)
+}
+
+// OnPan registers fn to be called with the latest TwoFingerPan data on every update frame a two
+// finger pan gesture is being made. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnPan(fn func(TwoFingerPan)) func() {
+	return on(tt, &tt.onPan, fn)
+}
+
+// OnSwipe registers fn to be called with every one finger swipe, on the update frame the finger
+// is released. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnSwipe(fn func(Swipe)) func() {
+	return on(tt, &tt.onSwipe[DirNone], fn)
+}
+
+// OnSwipeUp registers fn to be called with every one finger swipe up, on the update frame the finger
+// is released. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnSwipeUp(fn func(Swipe)) func() {
+	return on(tt, &tt.onSwipe[DirUp], fn)
+}
+
+// OnSwipeDown registers fn to be called with every one finger swipe down, on the update frame the finger
+// is released. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnSwipeDown(fn func(Swipe)) func() {
+	return on(tt, &tt.onSwipe[DirDown], fn)
+}
+
+// OnSwipeLeft registers fn to be called with every one finger swipe left, on the update frame the finger
+// is released. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnSwipeLeft(fn func(Swipe)) func() {
+	return on(tt, &tt.onSwipe[DirLeft], fn)
+}
+
+// OnSwipeRight registers fn to be called with every one finger swipe right, on the update frame the finger
+// is released. It returns a function that unregisters fn.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OnSwipeRight(fn func(Swipe)) func() {
+	return on(tt, &tt.onSwipe[DirRight], fn)
+}
+
+// OffAll unregisters every callback.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) OffAll() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	tt.onTap.clear()
+	tt.onDoubleTap.clear()
+	tt.onPinch.clear()
+	tt.onPan.clear()
+	for i := range tt.onSwipe {
+		tt.onSwipe[i].clear()
+	}
+}
+
+// dispatch runs the callbacks for the gestures recognized in the last update frame.
+func (tt *TouchTracker) dispatch() {
+	tt.m.RLock()
+	// Callbacks and gestures are only copied out when there's something to report,
+	// so frames without gestures don't allocate.
+	var taps []Tap
+	var onTap []func(Tap)
+	if len(tt.taps) > 0 {
+		onTap = tt.onTap.snapshot()
+		taps = make([]Tap, len(tt.taps))
+		for i, tap := range tt.taps {
+			taps[i] = tt.worldTap(tap.Tap)
+		}
+	}
+
+	var doubleTaps []Tap
+	var onDoubleTap []func(Tap)
+	if tt.doubleTap != nil {
+		onDoubleTap = tt.onDoubleTap.snapshot()
+		doubleTaps = append(doubleTaps, tt.worldTap(*tt.doubleTap))
+	}
+
+	var pinches []Pinch
+	var onPinch []func(Pinch)
+	if tt.pinch != nil && !tt.disabled.has(GesturePinch) && len(tt.onPinch.list) > 0 {
+		onPinch = tt.onPinch.snapshot()
+		pinches = append(pinches, tt.worldPinch(*tt.pinch))
+	}
+
+	var pans []TwoFingerPan
+	var onPan []func(TwoFingerPan)
+	if tt.pan != nil && !tt.disabled.has(GesturePan) && len(tt.onPan.list) > 0 {
+		onPan = tt.onPan.snapshot()
+		pans = append(pans, tt.worldPan(*tt.pan))
+	}
+
+	var swipes []Swipe
+	var onSwipe, onSwipeDir []func(Swipe)
+	if tt.swipe != nil {
+		swipes = append(swipes, tt.worldSwipe(*tt.swipe))
+		onSwipe = tt.onSwipe[DirNone].snapshot()
+		if tt.swipe.Direction != DirNone {
+			onSwipeDir = tt.onSwipe[tt.swipe.Direction].snapshot()
+		}
+	}
+	tt.m.RUnlock()
+
+	run(onTap, taps)
+	run(onDoubleTap, doubleTaps)
+	run(onPinch, pinches)
+	run(onPan, pans)
+	run(onSwipe, swipes)
+	run(onSwipeDir, swipes)
+}
+
+// run calls every function in fns with each value in values.
+func run[T any](fns []func(T), values []T) {
+	for _, v := range values {
+		for _, fn := range fns {
+			fn(v)
+		}
+	}
+}
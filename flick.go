@@ -0,0 +1,52 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "math"
+
+// flick is a touch released while moving fast, along with where it was pressed.
+type flick struct {
+	originX, originY int
+	vx, vy           float64
+}
+
+// WithFlickMinVelocity sets the minimum speed, in pixels per frame, a touch must be moving at
+// when released to be reported by FlickFromPoint rather than as a plain release. Defaults to 5.
+func WithFlickMinVelocity(pxPerFrame float64) Option {
+	return func(tt *TouchTracker) {
+		tt.flickMinVelocity = pxPerFrame
+	}
+}
+
+// releaseFlick checks if touch t was flicked when released, and records it if so.
+func (tt *TouchTracker) releaseFlick(t *touch) {
+	if tt.flick != nil || t.consumed || tt.suppressed(GestureFlick, t) {
+		return
+	}
+	vx, vy, ok := t.velocities.smoothed(tt.flickSampleWindow)
+	if !ok || math.Hypot(vx, vy) < tt.flickMinVelocity {
+		return
+	}
+	t.gestures.add(GestureFlick)
+	tt.record(GestureFlick, t.currX, t.currY)
+	tt.flick = &flick{originX: t.originX, originY: t.originY, vx: vx, vy: vy}
+}
+
+// FlickFromPoint returns where a touch released in the last update frame was pressed, and the
+// velocity it was moving at when released, in pixels per frame, if it was fast enough to be a flick.
+//
+// This is the drag-and-release aiming of a slingshot: the origin is the anchor and the velocity
+// is the launch vector. The velocity is averaged over the latest frames, as set by WithFlickSampleWindow,
+// to smooth out the deceleration right before lifting the finger. Flicks are not recorded as taps.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) FlickFromPoint() (originX, originY int, vx, vy float64, ok bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.flick != nil && !tt.handled.has(GestureFlick) {
+		f := tt.flick
+		x, y := tt.toWorld(f.originX, f.originY)
+		return x, y, f.vx, f.vy, true
+	}
+	return -1, -1, 0, 0, false
+}
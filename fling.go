@@ -0,0 +1,80 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "math"
+
+// WithFlingVelocity sets the minimum average speed, in pixels per frame, a single finger
+// must have when released to be a fling. Defaults to 10.
+func WithFlingVelocity(pxPerFrame float64) Option {
+	return func(tt *TouchTracker) {
+		tt.flingVelocity = pxPerFrame
+	}
+}
+
+// WithFlingVelocityPerSecond sets the minimum average speed, in pixels per second, a single finger
+// must have when released to be a fling, so the threshold doesn't depend on the frame rate.
+// When set, it takes precedence over WithFlingVelocity. Defaults to 0, which uses pixels per frame.
+func WithFlingVelocityPerSecond(pxPerSecond float64) Option {
+	return func(tt *TouchTracker) {
+		tt.flingVelocityPerSecond = pxPerSecond
+	}
+}
+
+// releaseFling checks if touch t was flung when released, and records it if so.
+// touching is the number of touches tracked before this frame's releases.
+func (tt *TouchTracker) releaseFling(t *touch, touching int) {
+	if tt.fling != nil || touching != 1 || t.consumed || tt.suppressed(GestureFling, t) {
+		return
+	}
+	frames := float64(max(t.duration, 1))
+	vx, vy := float64(t.currX-t.originX)/frames, float64(t.currY-t.originY)/frames
+	vsx, vsy := tt.averagePerSecond(t, float64(t.currX-t.originX), float64(t.currY-t.originY))
+	if tt.flingVelocityPerSecond > 0 {
+		if math.Hypot(vsx, vsy) < tt.flingVelocityPerSecond {
+			return
+		}
+	} else if math.Hypot(vx, vy) < tt.flingVelocity {
+		return
+	}
+
+	// Take the direction from the latest velocity, like swipes do, so a fling that curves at
+	// the end goes where the finger was heading when released.
+	dir := directionOf(vx, vy)
+	if svx, svy, ok := t.velocities.smoothed(tt.flickSampleWindow); ok && (svx != 0 || svy != 0) {
+		dir = directionOf(svx, svy)
+	}
+
+	t.gestures.add(GestureFling)
+	tt.record(GestureFling, t.currX, t.currY)
+	tt.fling = &Fling{
+		StartX:    t.originX,
+		StartY:    t.originY,
+		EndX:      t.currX,
+		EndY:      t.currY,
+		VelocityX: vx,
+		VelocityY: vy,
+		Direction: dir,
+
+		VelocityPerSecondX: vsx,
+		VelocityPerSecondY: vsy,
+	}
+}
+
+// Fling returns the Fling data if a single finger was flung (released fast enough) in the last update frame.
+//
+// Only a finger released while no other finger touches the screen can be a fling, so fingers lifted
+// together in the same frame aren't. Flings are not recorded as taps.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Fling() (Fling, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.fling != nil && !tt.handled.has(GestureFling) {
+		f := *tt.fling
+		f.StartX, f.StartY = tt.toWorld(f.StartX, f.StartY)
+		f.EndX, f.EndY = tt.toWorld(f.EndX, f.EndY)
+		return f, true
+	}
+	return Fling{}, false
+}
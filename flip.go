@@ -0,0 +1,38 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// WithFlipY reports coordinates with the Y axis flipped, as y' = height - y, for games with the origin
+// at the bottom-left corner of a screen of the given height.
+//
+// Gestures are still recognized in screen space. The flip applies to the positions reported for gestures,
+// before WithCoordinateTransform if set, and to the positions reported by raw touch queries like
+// TouchPositions, Touch, TouchBounds, TouchCentroid, GetFirstTouchPosition and FrameInput.
+// Velocities, angles and directions keep the screen orientation, and the rectangles and points given to
+// InRegion, SubTracker and NewVirtualJoystick are in screen space.
+func WithFlipY(height int) Option {
+	return func(tt *TouchTracker) {
+		tt.flipY = true
+		tt.flipHeight = height
+	}
+}
+
+// WithFlipX reports coordinates with the X axis flipped, as x' = width - x, for a screen of the given width.
+// It applies to the same coordinates as WithFlipY.
+func WithFlipX(width int) Option {
+	return func(tt *TouchTracker) {
+		tt.flipX = true
+		tt.flipWidth = width
+	}
+}
+
+// flip applies the axis flips to a point in screen space.
+func (tt *TouchTracker) flip(x, y int) (int, int) {
+	if tt.flipX {
+		x = tt.flipWidth - x
+	}
+	if tt.flipY {
+		y = tt.flipHeight - y
+	}
+	return x, y
+}
@@ -0,0 +1,27 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// WithFocusSuppression sets for how many frames after the app regains focus new touches are
+// ignored, so the tap that brings the app back doesn't also trigger an in-game action.
+//
+// Touches pressed in that window, or while the app is not focused, don't take part in
+// any gesture until released. Defaults to 3 frames, and 0 disables it.
+func WithFocusSuppression(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.focusSuppression = frames
+	}
+}
+
+// updateFocus tracks focus transitions and reports if new touches must be ignored this frame.
+func (tt *TouchTracker) updateFocus() bool {
+	focused := isFocused(tt.input)
+	if focused && !tt.wasFocused {
+		tt.refocusFrame = tt.frame
+	}
+	tt.wasFocused = focused
+	if tt.focusSuppression <= 0 {
+		return false
+	}
+	return !focused || (tt.refocusFrame > 0 && tt.frame-tt.refocusFrame < tt.focusSuppression)
+}
@@ -0,0 +1,113 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// forcePressMaxMovement is how far, in pixels, a touch can move from its origin
+// and still be considered stationary for a force press.
+const forcePressMaxMovement = 10
+
+// ForceProvider reports the pressure applied by touches on platforms that expose it.
+//
+// Ebiten doesn't surface touch pressure, so it must be plugged in by the user
+// with WithForceProvider.
+type ForceProvider interface {
+	// TouchForce returns the force applied by the touch, normalized between 0 and 1,
+	// or false if it's not available.
+	TouchForce(id ebiten.TouchID) (float64, bool)
+}
+
+// WithForceProvider sets the source of touch pressure data used to detect force presses.
+//
+// Without a provider no force press is ever detected.
+func WithForceProvider(p ForceProvider) Option {
+	return func(tt *TouchTracker) {
+		tt.forceProvider = p
+	}
+}
+
+// WithForceThreshold sets the normalized force a touch must reach to be a force press.
+// Defaults to 0.75.
+func WithForceThreshold(force float64) Option {
+	return func(tt *TouchTracker) {
+		tt.forceThreshold = force
+	}
+}
+
+// updateForce reads the force of touch t and reports if it just crossed the force threshold
+// while roughly stationary.
+func (tt *TouchTracker) updateForce(id ebiten.TouchID, t *touch) bool {
+	if tt.forceProvider == nil {
+		return false
+	}
+	prev := t.force
+	t.force, t.hasForce = tt.forceProvider.TouchForce(id)
+	if t.hasForce {
+		t.peakForce = max(t.peakForce, t.force)
+	}
+	if !t.hasForce || t.consumed || tt.suppressed(GestureForcePress, t) {
+		return false
+	}
+	moved := distance2d(t.originX, t.originY, t.currX, t.currY)
+	return prev < tt.forceThreshold && t.force >= tt.forceThreshold && moved <= forcePressMaxMovement
+}
+
+// ForcePressed returns the position of a touch that was pressed harder than the force threshold
+// in the last update frame, while staying roughly in place.
+//
+// It only reports the frame the force crosses the threshold. The force has to drop
+// below the threshold before the same touch can be force pressed again.
+// Touches that were force pressed are not recorded as taps when released.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ForcePressed() (int, int, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.forcePressed && !tt.handled.has(GestureForcePress) {
+		x, y := tt.toWorld(tt.forcePressX, tt.forcePressY)
+		return x, y, true
+	}
+	return -1, -1, false
+}
+
+// WithForceTapThreshold sets the normalized force a tap must be pressed with to be a force tap.
+// Defaults to 0.5.
+func WithForceTapThreshold(force float64) Option {
+	return func(tt *TouchTracker) {
+		tt.forceTapThreshold = force
+	}
+}
+
+// Force returns the normalized force applied by the active touch with the given id, as of the last
+// update frame, or false if the touch is not active or its force is not available.
+//
+// Force is only available from the ForceProvider set with WithForceProvider.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Force(id ebiten.TouchID) (float64, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	t, ok := tt.touches[id]
+	if !ok || !t.hasForce {
+		return 0, false
+	}
+	return t.force, true
+}
+
+// ForceTap returns Tap coordinates if a single tap pressed harder than the force tap threshold
+// was made (released) in the last update frame.
+//
+// Force taps are still reported as regular taps. A touch pressed past the force press threshold
+// while staying in place is a force press instead, and is not a tap. Without a ForceProvider
+// no force tap is ever made.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ForceTap() (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if len(tt.taps) == 1 && !tt.handled.has(GestureTap) && tt.taps[0].force >= tt.forceTapThreshold && tt.taps[0].force > 0 {
+		return tt.worldTap(tt.taps[0].Tap), true
+	}
+	return Tap{}, false
+}
@@ -0,0 +1,96 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// FrameInput is the raw touch input of an update frame, to build custom gesture recognizers on.
+type FrameInput struct {
+	// Pressed holds the touches pressed in the frame, at the position they were pressed.
+	Pressed []TouchPoint
+	// Held holds every active touch, including the ones pressed in the frame.
+	Held []HeldTouch
+	// Released holds the touches released in the frame, at their last known position.
+	Released []TouchPoint
+}
+
+// HeldTouch is an active touch in a FrameInput.
+type HeldTouch struct {
+	ID ebiten.TouchID
+
+	X, Y         int
+	PrevX, PrevY int
+
+	// Duration is the amount of frames the touch has been pressed.
+	Duration int
+}
+
+// FrameInput returns the touches pressed, held and released in the last update frame.
+// The returned slices are owned by the caller.
+//
+// Positions are in screen space, regardless of WithCoordinateTransform, apart from the axis flips set by
+// WithFlipX and WithFlipY. Touches excluded from gesture recognition are included.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) FrameInput() FrameInput {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	in := FrameInput{
+		Pressed:  tt.flipPoints(tt.pressed),
+		Held:     make([]HeldTouch, 0, len(tt.touchIDs)),
+		Released: tt.flipPoints(tt.released),
+	}
+	for _, id := range tt.touchIDs {
+		if t, ok := tt.touches[id]; ok {
+			h := HeldTouch{ID: id, Duration: t.duration}
+			h.X, h.Y = tt.flip(t.currX, t.currY)
+			h.PrevX, h.PrevY = tt.flip(t.prevX, t.prevY)
+			in.Held = append(in.Held, h)
+		}
+	}
+	return in
+}
+
+// flipPoints returns a copy of points with the axis flips applied.
+func (tt *TouchTracker) flipPoints(points []TouchPoint) []TouchPoint {
+	var flipped []TouchPoint
+	for _, p := range points {
+		x, y := tt.flip(p.X, p.Y)
+		flipped = append(flipped, TouchPoint{ID: p.ID, X: x, Y: y})
+	}
+	return flipped
+}
+
+// JustTouched returns the positions of the touches pressed in the last update frame, regardless
+// of the gestures they take part in, like to give feedback on every touch. Only X and Y are set.
+// The returned slice is owned by the caller.
+//
+// Positions are converted like those of taps, by WithCoordinateTransform and the axis flips.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) JustTouched() []Tap {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return tt.pointTaps(tt.pressed)
+}
+
+// JustReleased returns the positions where touches were released in the last update frame,
+// whether they were a tap or not. Only X and Y are set. The returned slice is owned by the caller.
+//
+// Positions are converted like those of taps, by WithCoordinateTransform and the axis flips.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) JustReleased() []Tap {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return tt.pointTaps(tt.released)
+}
+
+// pointTaps returns the positions of points as taps.
+func (tt *TouchTracker) pointTaps(points []TouchPoint) []Tap {
+	var taps []Tap
+	for _, p := range points {
+		taps = append(taps, tt.worldTap(Tap{X: p.X, Y: p.Y}))
+	}
+	return taps
+}
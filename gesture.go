@@ -0,0 +1,210 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "slices"
+
+// gestureSet is a set of gesture kinds.
+type gestureSet uint32
+
+func (s gestureSet) has(k GestureKind) bool {
+	return s&(1<<k) != 0
+}
+
+func (s *gestureSet) add(k GestureKind) {
+	*s |= 1 << k
+}
+
+// SuppressionRule prevents a gesture from being recognized on the touches that already
+// took part in another gesture.
+//
+// For example, the rule {When: GesturePinch, Suppress: GestureTap} keeps the fingers of
+// a pinch from being recorded as taps when they are released.
+type SuppressionRule struct {
+	// When is the gesture that, once recognized, suppresses the other one.
+	When GestureKind
+	// Suppress is the gesture that can no longer be recognized on the touches involved.
+	Suppress GestureKind
+}
+
+// DefaultSuppressionRules returns the rules used unless WithSuppressionRules is given:
+// pinch and pan exclude each other, no other gesture can end as a tap, a drag or tap drag can't
+// become a long press, the fingers of a pinch or pan can't make any one finger gesture, and the
+// fingers of a three finger pan can't go on to make a pinch or pan.
+func DefaultSuppressionRules() []SuppressionRule {
+	return []SuppressionRule{
+		{When: GesturePinch, Suppress: GesturePan},
+		{When: GesturePan, Suppress: GesturePinch},
+		{When: GesturePinch, Suppress: GestureTap},
+		{When: GesturePan, Suppress: GestureTap},
+		{When: GestureForcePress, Suppress: GestureTap},
+		{When: GestureFlick, Suppress: GestureTap},
+		{When: GestureLongPress, Suppress: GestureTap},
+		{When: GestureSwipe, Suppress: GestureTap},
+		{When: GestureDrag, Suppress: GestureTap},
+		{When: GestureDrag, Suppress: GestureLongPress},
+		{When: GesturePinch, Suppress: GestureForcePress},
+		{When: GesturePan, Suppress: GestureForcePress},
+		{When: GesturePinch, Suppress: GestureFlick},
+		{When: GesturePan, Suppress: GestureFlick},
+		{When: GesturePinch, Suppress: GestureLongPress},
+		{When: GesturePan, Suppress: GestureLongPress},
+		{When: GesturePinch, Suppress: GestureSwipe},
+		{When: GesturePan, Suppress: GestureSwipe},
+		{When: GesturePinch, Suppress: GestureDrag},
+		{When: GesturePan, Suppress: GestureDrag},
+		{When: GesturePinch, Suppress: GestureThreeFingerPan},
+		{When: GesturePan, Suppress: GestureThreeFingerPan},
+		{When: GestureThreeFingerPan, Suppress: GestureTap},
+		{When: GestureThreeFingerPan, Suppress: GesturePinch},
+		{When: GestureThreeFingerPan, Suppress: GesturePan},
+		{When: GestureFling, Suppress: GestureTap},
+		{When: GesturePinch, Suppress: GestureFling},
+		{When: GesturePan, Suppress: GestureFling},
+		{When: GestureTapDrag, Suppress: GestureTap},
+		{When: GestureTapDrag, Suppress: GestureLongPress},
+		{When: GesturePinch, Suppress: GestureTapDrag},
+		{When: GesturePan, Suppress: GestureTapDrag},
+	}
+}
+
+// WithSuppressionRules replaces the default suppression rules, giving control over which
+// gesture takes precedence over which.
+//
+// Rules are applied to each touch separately: once a touch takes part in the When gesture,
+// it can't take part in the Suppress gesture. Recognizers run in a fixed order every Update,
+// force press first, then long press, drag and tap drag, pinch, pan, three finger pan, and flicks,
+// swipes, flings and taps on release, so a rule can only suppress gestures that are checked after
+// the When gesture is recognized. Passing no rules lets every gesture be recognized independently.
+func WithSuppressionRules(rules ...SuppressionRule) Option {
+	return func(tt *TouchTracker) {
+		tt.setSuppressionRules(rules)
+	}
+}
+
+func (tt *TouchTracker) setSuppressionRules(rules []SuppressionRule) {
+	tt.suppressedBy = make(map[GestureKind]gestureSet, len(rules))
+	for _, r := range rules {
+		s := tt.suppressedBy[r.Suppress]
+		s.add(r.When)
+		tt.suppressedBy[r.Suppress] = s
+	}
+}
+
+// WithGesturePriority sets which gesture is committed when the movement of the same fingers
+// passes the threshold of more than one in the same update frame, from the highest to the lowest
+// priority, and the others are suppressed on those fingers. For example, passing GesturePan
+// before GesturePinch makes two fingers moving apart while sliding always pan.
+//
+// It currently decides between pinch and pan, the gestures classified from the same two fingers.
+// Kinds left out rank below the ones given. By default, and between kinds that are both left out,
+// the gesture whose threshold was passed by the widest margin is committed.
+func WithGesturePriority(kinds ...GestureKind) Option {
+	return func(tt *TouchTracker) {
+		tt.priority = slices.Clone(kinds)
+	}
+}
+
+// outranks returns if gesture a wins over gesture b when both are plausible, by the priority set
+// with WithGesturePriority, or by their evidence if the priority doesn't tell them apart.
+func (tt *TouchTracker) outranks(a, b GestureKind, evidenceA, evidenceB float64) bool {
+	ra, rb := slices.Index(tt.priority, a), slices.Index(tt.priority, b)
+	switch {
+	case ra == rb:
+		return evidenceA >= evidenceB
+	case ra < 0:
+		return false
+	case rb < 0:
+		return true
+	}
+	return ra < rb
+}
+
+// suppressed returns if gesture k can't be recognized on any of the given touches,
+// or at all if it's disabled.
+func (tt *TouchTracker) suppressed(k GestureKind, touches ...*touch) bool {
+	if tt.disabled.has(k) {
+		return true
+	}
+	by := tt.suppressedBy[k]
+	for _, t := range touches {
+		if t.gestures&by != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableGesture turns the recognizer of a gesture kind on or off, like pinch in scenes that don't
+// use it, so it's not mistaken for other gestures. Every gesture is enabled by default.
+//
+// A disabled gesture is not recognized, its accessors report nothing, its callbacks aren't called,
+// and it no longer suppresses other gestures on new touches. A gesture in progress when disabled
+// stops being reported, and is reported again from the next update frame if enabled before it
+// ends. Disabling GesturePinch also turns off GestureRotate, as a rotation is made with a pinch,
+// while disabling GestureRotate keeps a rotating pinch reported as a pinch that isn't rotating.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) EnableGesture(kind GestureKind, on bool) {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	if on {
+		tt.disabled &^= 1 << kind
+	} else {
+		tt.disabled.add(kind)
+		tt.handled.add(kind)
+	}
+}
+
+// CurrentGesture returns the highest priority gesture being made in the last update frame,
+// consistently with what the accessor of each gesture reports. From highest to lowest priority:
+//
+//   - GestureThreeFingerPan while in progress.
+//   - GestureRotate while a pinch in progress is rotating, as reported by Pinch.IsRotating.
+//   - GesturePinch and GesturePan while in progress.
+//   - GestureDrag, GestureTapDrag, GestureLongPress and GestureTwoFingerHold while in progress.
+//   - GestureForcePress on the frame it's recognized.
+//   - GestureFling, GestureSwipe and GestureFlick on the frame the finger is released.
+//   - GestureTap on the frame a tap, double tap or triple tap is made.
+//   - GestureTapPending while a finger that could still be a tap touches the screen.
+//   - GestureNone otherwise.
+//
+// Gestures consumed with Consume are skipped.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) CurrentGesture() GestureKind {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	switch {
+	case tt.threePan != nil && !tt.handled.has(GestureThreeFingerPan):
+		return GestureThreeFingerPan
+	case tt.pinch != nil && !tt.handled.has(GesturePinch) && !tt.handled.has(GestureRotate) && tt.pinch.IsRotating():
+		return GestureRotate
+	case tt.pinch != nil && !tt.handled.has(GesturePinch):
+		return GesturePinch
+	case tt.pan != nil && !tt.handled.has(GesturePan):
+		return GesturePan
+	case tt.drag != nil && !tt.handled.has(GestureDrag):
+		return GestureDrag
+	case tt.tapDrag != nil && !tt.handled.has(GestureTapDrag):
+		return GestureTapDrag
+	case tt.longPress != nil && !tt.handled.has(GestureLongPress):
+		return GestureLongPress
+	case tt.twoFingerHold != nil && !tt.handled.has(GestureTwoFingerHold):
+		return GestureTwoFingerHold
+	case tt.forcePressed && !tt.handled.has(GestureForcePress):
+		return GestureForcePress
+	case tt.fling != nil && !tt.handled.has(GestureFling):
+		return GestureFling
+	case tt.swipe != nil && !tt.handled.has(GestureSwipe):
+		return GestureSwipe
+	case tt.flick != nil && !tt.handled.has(GestureFlick):
+		return GestureFlick
+	case (len(tt.taps) > 0 || tt.doubleTap != nil || tt.tripleTap != nil) && !tt.handled.has(GestureTap):
+		return GestureTap
+	case len(tt.pendingTaps) > 0 || tt.hasTapCompanion(nil):
+		return GestureTapPending
+	default:
+		return GestureNone
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// WithGestureGracePeriod keeps a pinch or pan going for the given amount of frames after one of
+// its fingers is released, so a finger that briefly loses contact on a flaky touchscreen and lands
+// again resumes the same gesture instead of restarting it with a visible snap.
+//
+// While waiting, the gesture keeps reporting the last positions of its fingers. The next finger
+// pressed takes the place of the one lost. If none is pressed in time, the gesture ends without
+// coasting, and if the other finger is released too, it ends right away.
+// Defaults to 0, which ends the gesture right away.
+func WithGestureGracePeriod(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.gracePeriod = frames
+	}
+}
+
+// lostTouch is a finger of a pinch or pan released within the grace period.
+type lostTouch struct {
+	id       ebiten.TouchID
+	gestures gestureSet
+	frame    int
+}
+
+// keepLost holds on to touch t, just released, if it's a finger of the pinch or pan in progress
+// and the grace period allows it, and reports if it did.
+func (tt *TouchTracker) keepLost(id ebiten.TouchID, t *touch) bool {
+	if tt.gracePeriod <= 0 || tt.lost != nil || t.consumed {
+		return false
+	}
+	inPinch := tt.pinch != nil && (id == tt.pinch.ID1 || id == tt.pinch.ID2)
+	inPan := tt.pan != nil && !tt.pan.coasting && (id == tt.pan.ID1 || id == tt.pan.ID2)
+	if !inPinch && !inPan {
+		return false
+	}
+	tt.lost = &lostTouch{id: id, gestures: t.gestures, frame: tt.frame}
+	return true
+}
+
+// resumeLost hands the place of the lost finger, if any, to touch t just pressed.
+func (tt *TouchTracker) resumeLost(id ebiten.TouchID, t *touch) {
+	if tt.lost == nil || t.consumed {
+		return
+	}
+	swap := func(ref *ebiten.TouchID) {
+		if *ref == tt.lost.id {
+			*ref = id
+		}
+	}
+	if tt.pinch != nil {
+		swap(&tt.pinch.ID1)
+		swap(&tt.pinch.ID2)
+	}
+	if tt.pan != nil {
+		swap(&tt.pan.ID1)
+		swap(&tt.pan.ID2)
+	}
+	t.gestures |= tt.lost.gestures
+	tt.lost = nil
+}
+
+// updateLost keeps the gesture of the lost finger still while waiting for it to land again,
+// and ends it once the grace period is over.
+func (tt *TouchTracker) updateLost() {
+	if tt.lost == nil {
+		return
+	}
+	if tt.pinch == nil && (tt.pan == nil || tt.pan.coasting) {
+		tt.lost = nil
+		return
+	}
+	if tt.frame-tt.lost.frame > tt.gracePeriod {
+		if tt.pinch != nil {
+			tt.pinchEnded = tt.pinch
+			tt.pinch = nil
+			tt.pinchEndFrame = tt.frame
+		}
+		if tt.pan != nil {
+			tt.panEnded = tt.pan
+			tt.pan = nil
+		}
+		tt.lost = nil
+		return
+	}
+	if tt.pinch != nil {
+		tt.pinch.PrevDistance = tt.pinch.Distance
+	}
+	if tt.pan != nil {
+		tt.pan.PrevX, tt.pan.PrevY = tt.pan.LastX, tt.pan.LastY
+	}
+}
@@ -0,0 +1,6 @@
+//go:build touchutils_headless
+
+package ebiten_touchutils
+
+// TouchID identifies a touch. Builds without the touchutils_headless tag use ebiten.TouchID.
+type TouchID int
@@ -0,0 +1,48 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// GestureEvent is an entry of the gesture history, recorded when a gesture is recognized.
+type GestureEvent struct {
+	Kind GestureKind
+
+	// X, Y is where the gesture was recognized: the position of the finger for one finger
+	// gestures, and the midpoint or centroid of the fingers for multi finger gestures.
+	X, Y int
+
+	// Frame is the number of Update calls made when the gesture was recognized.
+	Frame int
+}
+
+// WithHistory keeps the latest size recognized gestures, to be read with History.
+// History is disabled by default.
+func WithHistory(size int) Option {
+	return func(tt *TouchTracker) {
+		tt.historySize = max(size, 0)
+		tt.history = make([]GestureEvent, 0, tt.historySize)
+	}
+}
+
+// record appends a recognized gesture to the history, dropping the oldest one if full.
+func (tt *TouchTracker) record(kind GestureKind, x, y int) {
+	if tt.historySize == 0 {
+		return
+	}
+	if len(tt.history) == tt.historySize {
+		copy(tt.history, tt.history[1:])
+		tt.history = tt.history[:tt.historySize-1]
+	}
+	tt.history = append(tt.history, GestureEvent{Kind: kind, X: x, Y: y, Frame: tt.frame})
+}
+
+// History returns a copy of the latest recognized gestures, oldest first, as enabled by WithHistory.
+//
+// Gestures that last several frames, like pinch, pan, drag, long press and force press, are recorded
+// once when first recognized.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) History() []GestureEvent {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return append([]GestureEvent(nil), tt.history...)
+}
@@ -0,0 +1,62 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// TwoFingerHold is the gesture of holding two fingers on the screen without moving them.
+type TwoFingerHold struct {
+	// X, Y is the midpoint between the fingers.
+	X, Y int
+
+	// Duration is for how many frames both fingers have been held down.
+	Duration int
+}
+
+// updateTwoFingerHold recognizes two fingers being held down in place, using the long press
+// duration and radius for each of them.
+func (tt *TouchTracker) updateTwoFingerHold() {
+	if len(tt.touchIDs) != 2 || tt.pinch != nil || tt.pan != nil {
+		return
+	}
+	t1, t2 := tt.touches[tt.touchIDs[0]], tt.touches[tt.touchIDs[1]]
+	for _, t := range []*touch{t1, t2} {
+		if t.consumed || t.gestures.has(GesturePinch) || t.gestures.has(GesturePan) {
+			return
+		}
+		if !tt.isLongPressDuration(t) || distance2d(t.originX, t.originY, t.currX, t.currY) > tt.longPressRadius {
+			return
+		}
+	}
+	if tt.suppressed(GestureTwoFingerHold, t1, t2) {
+		return
+	}
+	if !t1.gestures.has(GestureTwoFingerHold) || !t2.gestures.has(GestureTwoFingerHold) {
+		tt.record(GestureTwoFingerHold, (t1.currX+t2.currX)/2, (t1.currY+t2.currY)/2)
+	}
+	t1.gestures.add(GestureTwoFingerHold)
+	t2.gestures.add(GestureTwoFingerHold)
+	tt.frameGestures.twoFingerHold = TwoFingerHold{
+		X:        (t1.currX + t2.currX) / 2,
+		Y:        (t1.currY + t2.currY) / 2,
+		Duration: min(t1.duration, t2.duration),
+	}
+	tt.twoFingerHold = &tt.frameGestures.twoFingerHold
+}
+
+// TwoFingerHold returns the TwoFingerHold data while exactly two fingers are held down in place
+// past the long press duration, without making a pinch or pan.
+//
+// TwoFingerHold data updates every update frame, and stops being reported once the fingers move
+// farther than the long press radius or a pinch or pan is recognized. It can be turned off with
+// EnableGesture and hidden with Consume using GestureTwoFingerHold.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TwoFingerHold() (TwoFingerHold, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.twoFingerHold != nil && !tt.handled.has(GestureTwoFingerHold) {
+		h := *tt.twoFingerHold
+		h.X, h.Y = tt.toWorld(h.X, h.Y)
+		return h, true
+	}
+	return TwoFingerHold{}, false
+}
@@ -0,0 +1,68 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// TouchID identifies a touch. It's ebiten.TouchID, except in headless builds, which
+// don't depend on ebiten. See Tracker.
+type TouchID = ebiten.TouchID
+
+// InputSource is where a TouchTracker reads raw touch input from, by default straight from
+// ebiten and inpututil. Its methods mirror the ebiten functions of the same name.
+//
+// A custom source can script touch sequences frame by frame to test gesture handling without
+// a window. If it also has an IsFocused() bool method, it's used for WithFocusSuppression,
+// otherwise the app is considered always focused.
+type InputSource interface {
+	AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID
+	AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID
+	IsTouchJustReleased(id ebiten.TouchID) bool
+	TouchPosition(id ebiten.TouchID) (int, int)
+	TouchPressDuration(id ebiten.TouchID) int
+}
+
+// WithInputSource makes the tracker read touches from src instead of ebiten.
+func WithInputSource(src InputSource) Option {
+	return func(tt *TouchTracker) {
+		tt.input = src
+	}
+}
+
+// isFocused returns if the app is focused according to src, or true if src can't tell.
+func isFocused(src InputSource) bool {
+	if f, ok := src.(interface{ IsFocused() bool }); ok {
+		return f.IsFocused()
+	}
+	return true
+}
+
+// ebitenInput reads touches straight from ebiten.
+type ebitenInput struct{}
+
+func (ebitenInput) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return ebiten.AppendTouchIDs(touches)
+}
+
+func (ebitenInput) AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return inpututil.AppendJustPressedTouchIDs(touches)
+}
+
+func (ebitenInput) IsTouchJustReleased(id ebiten.TouchID) bool {
+	return inpututil.IsTouchJustReleased(id)
+}
+
+func (ebitenInput) TouchPosition(id ebiten.TouchID) (int, int) {
+	return ebiten.TouchPosition(id)
+}
+
+func (ebitenInput) TouchPressDuration(id ebiten.TouchID) int {
+	return inpututil.TouchPressDuration(id)
+}
+
+func (ebitenInput) IsFocused() bool {
+	return ebiten.IsFocused()
+}
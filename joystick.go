@@ -0,0 +1,95 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// VirtualJoystick is an on-screen analog stick driven by the touches of a TouchTracker.
+//
+// It latches onto the first touch that lands within its base, a circle around its center,
+// and follows it until released, even if it moves outside the base.
+type VirtualJoystick struct {
+	tt *TouchTracker
+
+	centerX, centerY int
+	radius           float64
+
+	active bool
+	id     ebiten.TouchID
+	dx, dy float64
+
+	m sync.RWMutex
+}
+
+// NewVirtualJoystick creates a joystick with its base centered at (centerX, centerY), in screen
+// coordinates, with the given radius in pixels, fed by the touches of tt.
+func NewVirtualJoystick(tt *TouchTracker, centerX, centerY int, radius float64) *VirtualJoystick {
+	return &VirtualJoystick{
+		tt:      tt,
+		centerX: centerX,
+		centerY: centerY,
+		radius:  radius,
+	}
+}
+
+// Update reads the touches of the tracker, and must be called on every update frame
+// after the tracker Update.
+//
+// This function is concurrent safe.
+func (j *VirtualJoystick) Update() {
+	j.m.Lock()
+	defer j.m.Unlock()
+	j.tt.m.RLock()
+	defer j.tt.m.RUnlock()
+
+	if j.active {
+		if _, ok := j.tt.touches[j.id]; !ok {
+			j.active = false
+		}
+	}
+	if !j.active {
+		for _, id := range j.tt.touchIDs {
+			t := j.tt.touches[id]
+			if distance2d(j.centerX, j.centerY, t.originX, t.originY) <= j.radius {
+				j.active = true
+				j.id = id
+				break
+			}
+		}
+	}
+	if !j.active || j.radius <= 0 {
+		j.dx, j.dy = 0, 0
+		return
+	}
+
+	t := j.tt.touches[j.id]
+	dx, dy := float64(t.currX-j.centerX), float64(t.currY-j.centerY)
+	if d := math.Hypot(dx, dy); d > j.radius {
+		dx, dy = dx/d*j.radius, dy/d*j.radius
+	}
+	j.dx, j.dy = dx/j.radius, dy/j.radius
+}
+
+// Active returns if a touch is driving the joystick.
+//
+// This function is concurrent safe.
+func (j *VirtualJoystick) Active() bool {
+	j.m.RLock()
+	defer j.m.RUnlock()
+	return j.active
+}
+
+// Vector returns the position of the touch driving the joystick relative to its center,
+// clamped to the radius and normalized to [-1, 1] on each axis. It's (0, 0) while inactive.
+//
+// This function is concurrent safe.
+func (j *VirtualJoystick) Vector() (dx, dy float64) {
+	j.m.RLock()
+	defer j.m.RUnlock()
+	return j.dx, j.dy
+}
@@ -0,0 +1,113 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// TapLongPressGap controls how a single finger released after the tap max duration, but before
+// being held for the long press duration, is classified.
+//
+// A finger held in place goes through these states: up to the tap max duration it's a tap if
+// released; from the long press duration on it's a long press, reported while held, and it's not
+// a tap when released. In the gap between both durations, if any, it's what TapLongPressGap sets.
+// With the tap max duration at or past the long press duration there's no gap, and the long press
+// wins from the frame it's recognized.
+type TapLongPressGap int
+
+const (
+	// TapGapIgnore makes a release in the gap neither a tap nor a long press.
+	TapGapIgnore TapLongPressGap = iota
+
+	// TapGapTap makes a release in the gap a tap, so every release before a long press is a tap.
+	TapGapTap
+)
+
+// WithTapLongPressGap sets how a release between the tap max duration and the long press duration
+// is classified. Defaults to TapGapIgnore.
+func WithTapLongPressGap(gap TapLongPressGap) Option {
+	return func(tt *TouchTracker) {
+		tt.tapLongPressGap = gap
+	}
+}
+
+// WithLongPressDuration sets for how many frames a finger must be held down to be a long press.
+// Defaults to 30.
+func WithLongPressDuration(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.longPressDuration = frames
+	}
+}
+
+// WithLongPressRadius sets how far, in pixels, a finger can drift from where it was pressed
+// and still be a long press. Defaults to 4.
+func WithLongPressRadius(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.longPressRadius = px
+	}
+}
+
+// updateLongPress recognizes a single finger being held down in place.
+func (tt *TouchTracker) updateLongPress() {
+	if len(tt.touchIDs) != 1 {
+		return
+	}
+	t := tt.touches[tt.touchIDs[0]]
+	if t.consumed || tt.suppressed(GestureLongPress, t) {
+		return
+	}
+	if distance2d(t.originX, t.originY, t.currX, t.currY) > tt.longPressRadius {
+		return
+	}
+	if !tt.isLongPressDuration(t) {
+		tt.longPressing = true
+		return
+	}
+	if !t.gestures.has(GestureLongPress) {
+		tt.record(GestureLongPress, t.currX, t.currY)
+	}
+	t.gestures.add(GestureLongPress)
+	tt.frameGestures.longPress = LongPress{X: t.currX, Y: t.currY, Duration: t.duration}
+	tt.longPress = &tt.frameGestures.longPress
+}
+
+// LongPress returns the latest LongPress data if a single finger is being held down in place
+// past the long press duration.
+//
+// LongPress data updates every update frame, and stops being reported if the finger moves
+// away or other fingers touch the screen. A finger that made a long press is not recorded as a tap
+// when released.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) LongPress() (LongPress, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.longPress != nil && !tt.handled.has(GestureLongPress) {
+		lp := *tt.longPress
+		lp.X, lp.Y = tt.toWorld(lp.X, lp.Y)
+		return lp, true
+	}
+	return LongPress{}, false
+}
+
+// LongPressJustStarted returns the LongPress data if a long press became active in the last update frame,
+// the first frame LongPress reports it after not reporting it in the previous one.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) LongPressJustStarted() (LongPress, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.longPress != nil && tt.started.has(GestureLongPress) && !tt.handled.has(GestureLongPress) {
+		lp := *tt.longPress
+		lp.X, lp.Y = tt.toWorld(lp.X, lp.Y)
+		return lp, true
+	}
+	return LongPress{}, false
+}
+
+// IsLongPressing returns if a single finger is being held down in place, but not yet
+// for long enough to be a long press. Useful to draw a charging indicator.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) IsLongPressing() bool {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return tt.longPressing
+}
@@ -0,0 +1,78 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// momentumMinVelocity is the speed, in pixels per frame, below which a coasting pan stops.
+const momentumMinVelocity = 0.5
+
+// momentum is the state of a two finger pan coasting after its fingers were released.
+type momentum struct {
+	x, y   float64
+	vx, vy float64
+}
+
+// WithMomentum makes a two finger pan keep coasting after one of its fingers is released,
+// like native scroll views, losing the given fraction of its velocity every frame.
+//
+// A friction of 0.05 glides for a while, and 0.3 stops almost right away. Friction must be
+// in (0, 1), and 0 disables momentum, which is the default.
+func WithMomentum(friction float64) Option {
+	return func(tt *TouchTracker) {
+		tt.momentumFriction = friction
+	}
+}
+
+// startMomentum turns the pan into a coasting one, launched with the velocity of its fingers.
+// Returns false if momentum is disabled or the pan was too slow to coast.
+func (tt *TouchTracker) startMomentum() bool {
+	if tt.momentumFriction <= 0 || tt.momentumFriction >= 1 || tt.paused {
+		return false
+	}
+	var vx, vy float64
+	for _, id := range []ebiten.TouchID{tt.pan.ID1, tt.pan.ID2} {
+		if t, ok := tt.touches[id]; ok {
+			if x, y, ok := t.velocities.smoothed(tt.flickSampleWindow); ok {
+				vx += x / 2
+				vy += y / 2
+			}
+		}
+	}
+	if math.Hypot(vx, vy) < momentumMinVelocity {
+		return false
+	}
+	tt.pan.coasting = true
+	tt.momentum = &momentum{x: float64(tt.pan.LastX), y: float64(tt.pan.LastY), vx: vx, vy: vy}
+	return true
+}
+
+// updateMomentum moves a coasting pan and slows it down, ending it once it's slow enough.
+func (tt *TouchTracker) updateMomentum() {
+	if tt.momentum == nil {
+		return
+	}
+	m := tt.momentum
+	m.x += m.vx
+	m.y += m.vy
+	m.vx *= 1 - tt.momentumFriction
+	m.vy *= 1 - tt.momentumFriction
+	tt.pan.PrevX, tt.pan.PrevY = tt.pan.LastX, tt.pan.LastY
+	tt.pan.LastX, tt.pan.LastY = int(math.Round(m.x)), int(math.Round(m.y))
+	if math.Hypot(m.vx, m.vy) < momentumMinVelocity {
+		tt.stopMomentum()
+	}
+}
+
+// stopMomentum ends a coasting pan, if any.
+func (tt *TouchTracker) stopMomentum() {
+	if tt.momentum != nil {
+		tt.momentum = nil
+		tt.panEnded = tt.pan
+		tt.pan = nil
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// MouseTouchID is the ID of the touch emulated with the left mouse button, see WithMouseEmulation.
+const MouseTouchID ebiten.TouchID = -1
+
+// WithMouseEmulation makes the left mouse button emulate a touch, so one finger gestures like
+// taps, drags and swipes can be tested with a mouse during development.
+//
+// Pressing the button presses the touch, moving the cursor moves it and releasing the button
+// releases it. Gestures that need more than one finger can't be emulated.
+func WithMouseEmulation(enabled bool) Option {
+	return func(tt *TouchTracker) {
+		tt.mouseEmulation = enabled
+	}
+}
+
+// mouseInput adds a touch emulated with the left mouse button to the touches of another source.
+type mouseInput struct {
+	src InputSource
+}
+
+func (m mouseInput) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	touches = m.src.AppendTouchIDs(touches)
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		touches = append(touches, MouseTouchID)
+	}
+	return touches
+}
+
+func (m mouseInput) AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	touches = m.src.AppendJustPressedTouchIDs(touches)
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		touches = append(touches, MouseTouchID)
+	}
+	return touches
+}
+
+func (m mouseInput) IsTouchJustReleased(id ebiten.TouchID) bool {
+	if id == MouseTouchID {
+		return inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+	}
+	return m.src.IsTouchJustReleased(id)
+}
+
+func (m mouseInput) TouchPosition(id ebiten.TouchID) (int, int) {
+	if id == MouseTouchID {
+		return ebiten.CursorPosition()
+	}
+	return m.src.TouchPosition(id)
+}
+
+func (m mouseInput) TouchPressDuration(id ebiten.TouchID) int {
+	if id == MouseTouchID {
+		return inpututil.MouseButtonPressDuration(ebiten.MouseButtonLeft)
+	}
+	return m.src.TouchPressDuration(id)
+}
+
+func (m mouseInput) IsFocused() bool {
+	return isFocused(m.src)
+}
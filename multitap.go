@@ -0,0 +1,117 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// WithMultiTapWindow sets for how many frames a released tap is held back while other fingers
+// that could still make a tap touch the screen, so fingers lifted a few frames apart are grouped
+// into a single two or three finger tap. Defaults to 3, and 0 only groups taps released in the
+// same frame.
+//
+// A tap made while no other finger touches the screen is reported right away.
+func WithMultiTapWindow(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.multiTapWindow = frames
+	}
+}
+
+// WithMultiTapMaxSpread sets how far apart, in pixels, the taps of a two or three finger tap can be.
+// Taps farther apart are reported as independent one finger taps, one per update frame, and fingers
+// farther away don't hold back a released tap. Defaults to 0, which doesn't limit the spread.
+func WithMultiTapMaxSpread(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.multiTapMaxSpread = px
+	}
+}
+
+// flushPendingTaps reports the taps released so far as a group, unless a companion finger may still
+// be lifted into the same multi finger tap within the window.
+func (tt *TouchTracker) flushPendingTaps() {
+	if len(tt.pendingTaps) > 0 && tt.pendingSince == 0 {
+		tt.pendingSince = tt.frame
+	}
+
+	// Taps split from a group for being too far apart are reported one per frame, before newer ones.
+	if len(tt.soloTaps) > 0 {
+		tt.addTap(tt.soloTaps[0])
+		tt.soloTaps = tt.soloTaps[1:]
+		return
+	}
+	if len(tt.pendingTaps) == 0 {
+		return
+	}
+
+	first := tt.pendingTaps[0]
+	if len(tt.pendingTaps) < 3 && tt.frame-tt.pendingSince < tt.multiTapWindow && tt.hasTapCompanion(&first) {
+		return
+	}
+	if tt.withinSpread(tt.pendingTaps) {
+		for _, tap := range tt.pendingTaps {
+			tt.addTap(tap)
+		}
+	} else {
+		tt.addTap(first)
+		tt.soloTaps = append(tt.soloTaps, tt.pendingTaps[1:]...)
+	}
+	tt.pendingTaps = tt.pendingTaps[:0]
+	tt.pendingSince = 0
+}
+
+// withinSpread returns if every pair of taps is within the multi tap max spread.
+func (tt *TouchTracker) withinSpread(taps []tapEvent) bool {
+	if tt.multiTapMaxSpread <= 0 {
+		return true
+	}
+	for i := range taps {
+		for j := i + 1; j < len(taps); j++ {
+			if distance2d(taps[i].X, taps[i].Y, taps[j].X, taps[j].Y) > tt.multiTapMaxSpread {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasTapCompanion returns if any finger touching the screen could still be released as a tap,
+// within the multi tap max spread of near if given.
+func (tt *TouchTracker) hasTapCompanion(near *tapEvent) bool {
+	for _, t := range tt.touches {
+		if t.consumed || tt.suppressed(GestureTap, t) || !tt.isTapDuration(t) {
+			continue
+		}
+		if near != nil && tt.multiTapMaxSpread > 0 && distance2d(near.X, near.Y, t.currX, t.currY) > tt.multiTapMaxSpread {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// tapDebounceRadius is how close, in pixels, a tap must be to the previous one to be dropped
+// as a duplicate by the tap debounce.
+const tapDebounceRadius = 10
+
+// WithTapDebounce drops a tap made within the given amount of frames and a few pixels of the previous
+// tap, for touch drivers that sometimes report a single tap twice. Unlike a double tap, the duplicate
+// is not reported at all. Defaults to 0, which keeps every tap.
+func WithTapDebounce(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.tapDebounce = frames
+	}
+}
+
+// isBounce returns if tap, released in the current frame, duplicates a recent tap.
+func (tt *TouchTracker) isBounce(tap tapEvent) bool {
+	if tt.tapDebounce <= 0 {
+		return false
+	}
+	for _, p := range tt.pendingTaps {
+		if distance2d(p.X, p.Y, tap.X, tap.Y) <= tapDebounceRadius {
+			return true
+		}
+	}
+	if n := len(tt.tapHistory); n > 0 {
+		last := tt.tapHistory[n-1]
+		return tt.frame-last.frame <= tt.tapDebounce && distance2d(last.X, last.Y, tap.X, tap.Y) <= tapDebounceRadius
+	}
+	return false
+}
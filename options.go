@@ -0,0 +1,108 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// Option configures a TouchTracker created with NewTouchTracker.
+type Option func(*TouchTracker)
+
+// WithTapMaxDuration sets the maximum amount of frames a finger can be touching the screen
+// to be recorded as a tap when released. A tap must also stay within the tap max movement.
+// Defaults to 30.
+func WithTapMaxDuration(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.tapMaxDuration = frames
+	}
+}
+
+// WithTapMaxMovement sets the distance, in pixels, under which a finger that moved is still
+// recorded as a tap when released, if it was also pressed for no longer than the tap max duration.
+// Defaults to 2.
+func WithTapMaxMovement(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.tapMaxMovement = px
+	}
+}
+
+// WithPinchThreshold sets how much, in pixels, the distance between two fingers must change
+// for them to be a pinch. Defaults to 10.
+func WithPinchThreshold(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.pinchThreshold = px
+	}
+}
+
+// WithPanThreshold sets how far, in pixels, two fingers must move horizontally or vertically
+// for them to be a pan. Defaults to 10.
+func WithPanThreshold(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.panThreshold = px
+	}
+}
+
+// WithPanActivationDistance sets how far, in pixels, the midpoint between two fingers must move
+// horizontally or vertically for them to start a pan, separately from the pan threshold used for
+// Direction. Once started, the pan follows the fingers from their true origin on every update frame.
+// Defaults to the pan threshold.
+func WithPanActivationDistance(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.panActivation = px
+	}
+}
+
+// panActivationDistance returns how far two fingers must move to start a pan.
+func (tt *TouchTracker) panActivationDistance() float64 {
+	if tt.panActivation > 0 {
+		return tt.panActivation
+	}
+	return tt.panThreshold
+}
+
+// WithPinchMinFrames sets for how many consecutive update frames the distance between two fingers
+// must stay past the pinch threshold for them to be a pinch, so the incidental spread of two fingers
+// lifted in a brisk two finger tap isn't mistaken for a pinch. Defaults to 2.
+func WithPinchMinFrames(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.pinchMinFrames = frames
+	}
+}
+
+// WithPinchDeadzone sets by how many pixels the distance between the fingers of a pinch must change
+// at least from one update frame to the next for Pinch.IsInward or Pinch.IsOutward to report it,
+// so sensor noise on a held pinch doesn't flip between both. Defaults to 1, so a slow pinch moving
+// a pixel per frame still has a direction.
+func WithPinchDeadzone(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.pinchDeadzone = px
+	}
+}
+
+// WithRotationThreshold sets by how many radians the fingers of a pinch must rotate around each
+// other for Pinch.IsRotating to report it, and for CurrentGesture to report GestureRotate.
+// Defaults to 0.26, about 15 degrees.
+func WithRotationThreshold(radians float64) Option {
+	return func(tt *TouchTracker) {
+		tt.rotationThreshold = radians
+	}
+}
+
+// WithMinPinchDistance sets the minimum distance, in pixels, between two fingers
+// when they first touch the screen for their movement to be recognized as a pinch.
+//
+// Fingers that start too close together produce tiny origin distances and
+// exaggerated scale ratios, so those gestures are rejected. It's also the floor
+// of the distance reported by a pinch in progress: frames where the fingers get
+// closer than this are ignored, so the scale doesn't jitter at near-zero distances.
+// Defaults to 0, which accepts any distance.
+func WithMinPinchDistance(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.minPinchDistance = px
+	}
+}
+
+// WithThumbTapSeparation sets the minimum horizontal or vertical distance, in pixels,
+// between the fingers of a two finger tap to be reported by TappedTwoApart. Defaults to 100.
+func WithThumbTapSeparation(px int) Option {
+	return func(tt *TouchTracker) {
+		tt.thumbTapSeparation = px
+	}
+}
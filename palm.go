@@ -0,0 +1,44 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// palmFrames is how many frames a touch must last not to be rejected as a palm, and how
+// soon after another touch a touch at the edge of the screen must land to be rejected.
+const palmFrames = 2
+
+// WithPalmRejection enables heuristic rejection of accidental touches, like the edge of a palm
+// grazing the screen. Disabled by default.
+//
+// Rejected touches are still tracked, but make no taps or gestures. A touch is rejected when
+// released within 2 frames of being pressed, or when it lands at the edge of the screen, within
+// the edge swipe margin, while another touch is taking part in a gesture or landed in the
+// last 2 frames. The edge check needs the screen size from WithScreenSize.
+func WithPalmRejection(enabled bool) Option {
+	return func(tt *TouchTracker) {
+		tt.palmRejection = enabled
+	}
+}
+
+// rejectPalmRelease marks a touch released too quickly after being pressed as consumed.
+func (tt *TouchTracker) rejectPalmRelease(t *touch) {
+	if tt.palmRejection && tt.frame-t.pressFrame <= palmFrames {
+		t.consumed = true
+	}
+}
+
+// isPalmPress returns if a touch landing at (x, y) must be rejected as a palm.
+func (tt *TouchTracker) isPalmPress(x, y int) bool {
+	if !tt.palmRejection || tt.screenW <= 0 || tt.screenH <= 0 {
+		return false
+	}
+	m := tt.edgeSwipeMargin
+	if x >= m && x < tt.screenW-m && y >= m && y < tt.screenH-m {
+		return false
+	}
+	for _, t := range tt.touches {
+		if !t.consumed && (t.gestures != 0 || tt.frame-t.pressFrame <= palmFrames) {
+			return true
+		}
+	}
+	return false
+}
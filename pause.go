@@ -0,0 +1,64 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// PauseInput controls what happens to the input made around a pause.
+type PauseInput int
+
+const (
+	// PauseDrop ignores every touch that is active while the tracker is paused until it's released,
+	// so nothing that started before or during the pause fires gestures after Resume.
+	PauseDrop PauseInput = iota
+
+	// PauseQueue keeps the touches made around a pause alive. A touch that is still held on Resume
+	// can still be recorded as a tap when released, and the latest tap made while paused is
+	// delivered on the first update frame after Resume.
+	PauseQueue
+)
+
+// WithPauseInput sets how input made around Pause and Resume is handled. Defaults to PauseDrop.
+func WithPauseInput(p PauseInput) Option {
+	return func(tt *TouchTracker) {
+		tt.pauseInput = p
+	}
+}
+
+// Pause stops recognizing gestures until Resume is called, and cancels any pinch or pan in progress.
+//
+// Update must still be called every frame while paused, so the tracker keeps up
+// with the touches being pressed and released. Touch positions and counts keep being
+// reported, but no gesture is.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Pause() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	tt.paused = true
+	tt.pinch = nil
+	tt.pan = nil
+	tt.threePan = nil
+	tt.momentum = nil
+	if tt.pauseInput == PauseDrop {
+		for _, t := range tt.touches {
+			t.consumed = true
+		}
+	}
+}
+
+// Resume starts recognizing gestures again after a Pause.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Resume() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	tt.paused = false
+}
+
+// IsPaused returns if the tracker is paused.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) IsPaused() bool {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return tt.paused
+}
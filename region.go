@@ -0,0 +1,188 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// regionInput only lets through the touches that start inside a rectangle,
+// with their positions relative to its top-left corner.
+type regionInput struct {
+	src  InputSource
+	rect image.Rectangle
+
+	// admitted holds the touches that started inside rect and are still active.
+	admitted map[ebiten.TouchID]struct{}
+	active   map[ebiten.TouchID]struct{}
+
+	// touchIDs and justPressed are the buffers the touches of src are read into.
+	touchIDs    []ebiten.TouchID
+	justPressed []ebiten.TouchID
+}
+
+func newRegionInput(src InputSource, rect image.Rectangle) *regionInput {
+	return &regionInput{
+		src:      src,
+		rect:     rect,
+		admitted: make(map[ebiten.TouchID]struct{}),
+		active:   make(map[ebiten.TouchID]struct{}),
+	}
+}
+
+func (r *regionInput) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	r.touchIDs = r.src.AppendTouchIDs(r.touchIDs[:0])
+	clear(r.active)
+	for _, id := range r.touchIDs {
+		r.active[id] = struct{}{}
+		if _, ok := r.admitted[id]; ok {
+			touches = append(touches, id)
+		}
+	}
+
+	// Forget the touches that are gone.
+	for id := range r.admitted {
+		if _, ok := r.active[id]; !ok {
+			delete(r.admitted, id)
+		}
+	}
+	return touches
+}
+
+func (r *regionInput) AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	r.justPressed = r.src.AppendJustPressedTouchIDs(r.justPressed[:0])
+	for _, id := range r.justPressed {
+		if image.Pt(r.src.TouchPosition(id)).In(r.rect) {
+			r.admitted[id] = struct{}{}
+			touches = append(touches, id)
+		}
+	}
+	return touches
+}
+
+func (r *regionInput) IsTouchJustReleased(id ebiten.TouchID) bool {
+	return r.src.IsTouchJustReleased(id)
+}
+
+func (r *regionInput) TouchPosition(id ebiten.TouchID) (int, int) {
+	x, y := r.src.TouchPosition(id)
+	return x - r.rect.Min.X, y - r.rect.Min.Y
+}
+
+func (r *regionInput) TouchPressDuration(id ebiten.TouchID) int {
+	return r.src.TouchPressDuration(id)
+}
+
+func (r *regionInput) IsFocused() bool {
+	return isFocused(r.src)
+}
+
+// SubTracker creates a tracker for a widget occupying rect, such as a minimap,
+// configured with the same options as tt plus opts.
+//
+// The options of tt that set the coordinate space, WithFlipX, WithFlipY, WithCoordinateTransform
+// and WithScreenSize, aren't carried over, as they're meant for screen coordinates. They apply
+// to the sub tracker only if given in opts.
+//
+// The sub tracker only processes the touches that start inside rect, and reports every
+// coordinate relative to the top-left corner of rect. A touch that starts inside and then
+// leaves rect is still handed to the sub tracker until released, with coordinates that may
+// fall outside the widget bounds, so gestures aren't cut short at the widget edge.
+// Touches that start outside rect are never handed to it, even if they move inside.
+//
+// The sub tracker is independent from tt, and its Update must be called on every Update frame too.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) SubTracker(rect image.Rectangle, opts ...Option) *TouchTracker {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	all := append(append([]Option{}, tt.opts...), opts...)
+	sub := NewTouchTracker(all...)
+	sub.input = newRegionInput(tt.input, rect)
+
+	own := NewTouchTracker(opts...)
+	sub.flipX, sub.flipWidth = own.flipX, own.flipWidth
+	sub.flipY, sub.flipHeight = own.flipY, own.flipHeight
+	sub.transform = own.transform
+	sub.screenW, sub.screenH = own.screenW, own.screenH
+	return sub
+}
+
+// Region is a view of a TouchTracker that only considers the touches that started inside
+// a rectangle, to attribute input to on-screen widgets like joysticks or buttons.
+//
+// Unlike SubTracker, a Region shares the state of its tracker and reports screen coordinates.
+// It doesn't need to be updated on its own, and can be created every frame.
+type Region struct {
+	tt   *TouchTracker
+	rect image.Rectangle
+}
+
+// InRegion returns a view of the tracker scoped to the touches that started inside rect.
+func (tt *TouchTracker) InRegion(rect image.Rectangle) Region {
+	return Region{tt: tt, rect: rect}
+}
+
+func (r Region) contains(x, y int) bool {
+	return image.Pt(x, y).In(r.rect)
+}
+
+// TouchCount returns how many fingers that started inside the region are touching the screen.
+//
+// This function is concurrent safe.
+func (r Region) TouchCount() int {
+	r.tt.m.RLock()
+	defer r.tt.m.RUnlock()
+	n := 0
+	for _, id := range r.tt.touchIDs {
+		if t, ok := r.tt.touches[id]; ok && r.contains(t.originX, t.originY) {
+			n++
+		}
+	}
+	return n
+}
+
+// IsTouching returns if any finger that started inside the region is touching the screen.
+//
+// This function is concurrent safe.
+func (r Region) IsTouching() bool {
+	return r.TouchCount() > 0
+}
+
+// TappedOne returns Tap coordinates if a single tap that started inside the region was made (released)
+// in the last update frame.
+//
+// This function is concurrent safe.
+func (r Region) TappedOne() (Tap, bool) {
+	r.tt.m.RLock()
+	defer r.tt.m.RUnlock()
+	if r.tt.handled.has(GestureTap) {
+		return Tap{}, false
+	}
+	var found Tap
+	n := 0
+	for _, tap := range r.tt.taps {
+		if r.contains(tap.originX, tap.originY) {
+			found = r.tt.worldTap(tap.Tap)
+			n++
+		}
+	}
+	if n == 1 {
+		return found, true
+	}
+	return Tap{}, false
+}
+
+// Drag returns the latest Drag data if a one finger drag that started inside the region is being made.
+//
+// This function is concurrent safe.
+func (r Region) Drag() (Drag, bool) {
+	r.tt.m.RLock()
+	defer r.tt.m.RUnlock()
+	if d := r.tt.drag; d != nil && !r.tt.handled.has(GestureDrag) && r.contains(d.StartX, d.StartY) {
+		return r.tt.worldDrag(*d), true
+	}
+	return Drag{}, false
+}
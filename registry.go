@@ -0,0 +1,48 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "sync"
+
+var (
+	managed   []*TouchTracker
+	managedMu sync.Mutex
+)
+
+// Manage registers tt to be updated by UpdateAll, and returns a function that unregisters it.
+//
+// This function is concurrent safe.
+func Manage(tt *TouchTracker) func() {
+	managedMu.Lock()
+	defer managedMu.Unlock()
+	managed = append(managed, tt)
+	return func() {
+		managedMu.Lock()
+		defer managedMu.Unlock()
+		for i, m := range managed {
+			if m == tt {
+				managed = append(managed[:i], managed[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// UpdateAll updates every tracker registered with Manage, so a game with many trackers only
+// needs a single call instead of threading Update through every component.
+//
+// Trackers are updated one after the other, in registration order, and each one runs its
+// callbacks before the next one is updated. UpdateAll should be called once at the start of
+// the game Update, before polling any tracker, so every tracker reflects the current frame.
+// Like Update, it must be called exactly once per frame.
+//
+// This function is concurrent safe.
+func UpdateAll() {
+	managedMu.Lock()
+	trackers := append([]*TouchTracker(nil), managed...)
+	managedMu.Unlock()
+
+	for _, tt := range trackers {
+		tt.Update()
+	}
+}
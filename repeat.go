@@ -0,0 +1,34 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// HoldRepeat returns if the active touch with the given id should trigger an auto-repeating action,
+// like an on-screen +/- button held down: on the update frame it's pressed, and then every interval
+// frames once it has been held for initialDelay frames, like keyboard key repeat.
+// With an interval of 0 or less, it only repeats once after the delay.
+//
+// It's based on the frames the tracker has seen the touch for, so it needs no state of its own and can
+// be checked for any touch, like the one pressed on a button region.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) HoldRepeat(id ebiten.TouchID, initialDelay, interval int) bool {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	t, ok := tt.touches[id]
+	if !ok {
+		return false
+	}
+	held := tt.frame - t.pressFrame
+	switch {
+	case held == 0:
+		return true
+	case held < initialDelay:
+		return false
+	case interval <= 0:
+		return held == initialDelay
+	default:
+		return (held-initialDelay)%interval == 0
+	}
+}
@@ -0,0 +1,116 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Reset drops every touch being tracked and every gesture in progress, so the tracker
+// starts fresh, as when transitioning between scenes. Configuration and callbacks are kept.
+//
+// Fingers still touching the screen are ignored until they are released.
+//
+// This function is concurrent safe, and can be called concurrently with Update.
+func (tt *TouchTracker) Reset() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+
+	for id := range tt.touches {
+		tt.ignored[id] = struct{}{}
+	}
+	tt.resetState()
+}
+
+// resetState drops every touch and every gesture in progress.
+func (tt *TouchTracker) resetState() {
+	clear(tt.touches)
+	tt.touchIDs = tt.touchIDs[:0]
+	tt.pressed = tt.pressed[:0]
+	tt.released = tt.released[:0]
+
+	tt.taps = tt.taps[:0]
+	tt.tapHistory = tt.tapHistory[:0]
+	tt.queuedTap = nil
+	tt.pendingTaps = tt.pendingTaps[:0]
+	tt.pendingSince = 0
+	tt.soloTaps = nil
+	tt.tapChain = nil
+	tt.doubleTap = nil
+	tt.tripleTap = nil
+	tt.singleTap = nil
+
+	tt.pinch = nil
+	tt.pinchEndFrame = 0
+	tt.pinchFrames = 0
+	tt.pan = nil
+	tt.lost = nil
+	tt.threePan = nil
+	tt.momentum = nil
+	tt.drag = nil
+	tt.tapDrag = nil
+	tt.dragging = false
+	tt.longPress = nil
+	tt.longPressing = false
+	tt.twoFingerHold = nil
+	tt.flick = nil
+	tt.pinchEnded = nil
+	tt.panEnded = nil
+	tt.fling = nil
+	tt.swipe = nil
+	tt.forcePressed = false
+	tt.ambiguity = 0
+	tt.allReleased = false
+	tt.handled = tt.disabled
+	tt.started = 0
+}
+
+// CancelGesture cancels the gestures in progress, like when a modal dialog opens mid-pinch,
+// and excludes the fingers touching the screen from gesture recognition until they are released.
+//
+// Unlike Reset, those touches are still tracked, so their positions and counts keep being
+// reported, and taps and gestures already made are kept.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) CancelGesture() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+
+	for _, t := range tt.touches {
+		t.consumed = true
+	}
+	tt.pendingTaps = tt.pendingTaps[:0]
+	tt.pendingSince = 0
+
+	tt.pinch = nil
+	tt.pinchFrames = 0
+	tt.pan = nil
+	tt.threePan = nil
+	tt.momentum = nil
+	tt.drag = nil
+	tt.tapDrag = nil
+	tt.dragging = false
+	tt.longPress = nil
+	tt.longPressing = false
+	tt.twoFingerHold = nil
+	tt.ambiguity = 0
+}
+
+// dropIgnored removes the ignored touches from ids, and forgets the ignored touches
+// that are no longer active.
+func (tt *TouchTracker) dropIgnored(ids []ebiten.TouchID) []ebiten.TouchID {
+	if len(tt.ignored) == 0 {
+		return ids
+	}
+	for id := range tt.ignored {
+		if !slices.Contains(ids, id) {
+			delete(tt.ignored, id)
+		}
+	}
+	return slices.DeleteFunc(ids, func(id ebiten.TouchID) bool {
+		_, ok := tt.ignored[id]
+		return ok
+	})
+}
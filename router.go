@@ -0,0 +1,259 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TouchRouter owns the raw touch input and hands it to stacked layers, like the panels of a UI,
+// each with its own TouchTracker. Every touch belongs to a single layer, the topmost one that
+// claims it when pressed, so other layers never see it unless it's handed over with PassTouch
+// or ClaimTouch.
+type TouchRouter struct {
+	m      sync.Mutex
+	input  InputSource
+	opts   []Option
+	layers []*routedInput
+
+	// owners holds the layer each active touch was handed to.
+	owners      map[ebiten.TouchID]*routedInput
+	scratch     []ebiten.TouchID
+	justPressed []ebiten.TouchID
+
+	// handovers holds the touches to hand to another layer on the next Update.
+	handovers []handover
+}
+
+// handover is a touch handed from one layer to another with PassTouch or ClaimTouch.
+type handover struct {
+	id   ebiten.TouchID
+	from *routedInput
+	// to is the layer taking the touch, or nil for the topmost layer beneath from that claims it.
+	to *routedInput
+}
+
+// NewTouchRouter creates a router reading touches as configured by opts, like WithInputSource.
+// The options are applied to every layer tracker too.
+func NewTouchRouter(opts ...Option) *TouchRouter {
+	return &TouchRouter{
+		input:  NewTouchTracker(opts...).input,
+		opts:   opts,
+		owners: make(map[ebiten.TouchID]*routedInput),
+	}
+}
+
+// Layer creates a tracker for a layer at depth z, configured with the router options plus opts.
+// Layers with a higher z are on top, and among layers with the same z the latest created is on top.
+//
+// A touch is handed to the topmost layer whose hit function returns true for the position it was
+// pressed at, and stays with it until released or handed over with PassTouch or ClaimTouch, even
+// if it moves out. A nil hit function claims every touch, as for a background layer. Hit functions
+// are called during the router Update, and can depend on the layer state, like a panel being hidden.
+//
+// The tracker reports screen coordinates, and it must not be updated on its own: the router
+// Update updates every layer.
+//
+// This function is concurrent safe.
+func (r *TouchRouter) Layer(z int, hit func(x, y int) bool, opts ...Option) *TouchTracker {
+	r.m.Lock()
+	defer r.m.Unlock()
+	all := append(append([]Option{}, r.opts...), opts...)
+	tt := NewTouchTracker(all...)
+	layer := &routedInput{src: r.input, tt: tt, z: z, hit: hit}
+	tt.input = layer
+
+	i := slices.IndexFunc(r.layers, func(l *routedInput) bool { return l.z <= z })
+	if i < 0 {
+		i = len(r.layers)
+	}
+	r.layers = slices.Insert(r.layers, i, layer)
+	return tt
+}
+
+// RemoveLayer removes the layer of tt, created with Layer. The touches it owns are not handed
+// to other layers.
+//
+// This function is concurrent safe.
+func (r *TouchRouter) RemoveLayer(tt *TouchTracker) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.layers = slices.DeleteFunc(r.layers, func(l *routedInput) bool { return l.tt == tt })
+}
+
+// PassTouch hands the touch with the given id, owned by the layer of tt, to the layers beneath it,
+// like a button passing a touch that starts dragging to the scrolling list it sits on.
+//
+// On the next Update the touch is handed to the topmost layer beneath whose hit function returns
+// true for its current position, which sees it as just pressed there, and the layer of tt sees
+// it as released, without it making a tap, swipe or fling. If no layer claims it, no layer owns
+// it anymore. Does nothing if the layer of tt doesn't own the touch.
+//
+// This function is concurrent safe.
+func (r *TouchRouter) PassTouch(tt *TouchTracker, id ebiten.TouchID) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if from, ok := r.owners[id]; ok && from.tt == tt {
+		r.handovers = append(r.handovers, handover{id: id, from: from})
+		tt.consumeTouch(id)
+	}
+}
+
+// ClaimTouch hands the touch with the given id to the layer of tt, whatever layer owns it,
+// like a scrolling list taking over a touch that started on one of its buttons.
+//
+// On the next Update the layer of tt sees the touch as just pressed at its current position,
+// and the layer that owned it sees it as released, without it making a tap, swipe or fling.
+// Does nothing if the touch isn't active or tt isn't a layer of the router.
+//
+// This function is concurrent safe.
+func (r *TouchRouter) ClaimTouch(tt *TouchTracker, id ebiten.TouchID) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	i := slices.IndexFunc(r.layers, func(l *routedInput) bool { return l.tt == tt })
+	if i < 0 || !slices.Contains(r.scratch, id) {
+		return
+	}
+	from := r.owners[id]
+	if from == r.layers[i] {
+		return
+	}
+	r.handovers = append(r.handovers, handover{id: id, from: from, to: r.layers[i]})
+	if from != nil {
+		from.tt.consumeTouch(id)
+	}
+}
+
+// handOver moves the touches handed over since the last Update to their new layers.
+func (r *TouchRouter) handOver() {
+	for _, h := range r.handovers {
+		if r.owners[h.id] != h.from || !slices.Contains(r.scratch, h.id) || r.input.IsTouchJustReleased(h.id) {
+			continue
+		}
+		to := h.to
+		if to == nil {
+			x, y := r.input.TouchPosition(h.id)
+			if i := slices.Index(r.layers, h.from); i >= 0 {
+				for _, l := range r.layers[i+1:] {
+					if l.hit == nil || l.hit(x, y) {
+						to = l
+						break
+					}
+				}
+			}
+		} else if !slices.Contains(r.layers, to) {
+			continue
+		}
+
+		delete(r.owners, h.id)
+		if h.from != nil {
+			h.from.released = append(h.from.released, h.id)
+		}
+		if to != nil {
+			r.owners[h.id] = to
+			to.pressed = append(to.pressed, h.id)
+		}
+	}
+	r.handovers = r.handovers[:0]
+}
+
+// Update hands the touch input of the current frame to the layers and updates their trackers,
+// from the topmost to the bottommost. It must be called on every Update frame instead of
+// the Update of the layer trackers.
+//
+// This function is concurrent safe.
+func (r *TouchRouter) Update() {
+	r.m.Lock()
+	for _, l := range r.layers {
+		l.ids = l.ids[:0]
+		l.pressed = l.pressed[:0]
+		l.released = l.released[:0]
+	}
+
+	// Forget the touches released before this frame, keeping the ones released in this frame
+	// so their layer can see the release.
+	r.scratch = r.input.AppendTouchIDs(r.scratch[:0])
+	for id := range r.owners {
+		if !slices.Contains(r.scratch, id) && !r.input.IsTouchJustReleased(id) {
+			delete(r.owners, id)
+		}
+	}
+
+	// Hand new touches to the topmost layer that claims them.
+	r.justPressed = r.input.AppendJustPressedTouchIDs(r.justPressed[:0])
+	for _, id := range r.justPressed {
+		x, y := r.input.TouchPosition(id)
+		delete(r.owners, id)
+		for _, l := range r.layers {
+			if l.hit == nil || l.hit(x, y) {
+				r.owners[id] = l
+				l.pressed = append(l.pressed, id)
+				break
+			}
+		}
+	}
+	r.handOver()
+	for _, id := range r.scratch {
+		if l, ok := r.owners[id]; ok {
+			l.ids = append(l.ids, id)
+		}
+	}
+
+	// Trackers are updated unlocked, as their callbacks may add or remove layers.
+	layers := slices.Clone(r.layers)
+	r.m.Unlock()
+
+	for _, l := range layers {
+		l.tt.Update()
+	}
+}
+
+// routedInput is the input of a router layer, holding the touches it was handed in the current frame.
+type routedInput struct {
+	src InputSource
+	tt  *TouchTracker
+	z   int
+	hit func(x, y int) bool
+
+	ids     []ebiten.TouchID
+	pressed []ebiten.TouchID
+	// released holds the touches handed to another layer in the current frame.
+	released []ebiten.TouchID
+}
+
+func (l *routedInput) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return append(touches, l.ids...)
+}
+
+func (l *routedInput) AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return append(touches, l.pressed...)
+}
+
+func (l *routedInput) IsTouchJustReleased(id ebiten.TouchID) bool {
+	return slices.Contains(l.released, id) || l.src.IsTouchJustReleased(id)
+}
+
+func (l *routedInput) TouchPosition(id ebiten.TouchID) (int, int) {
+	return l.src.TouchPosition(id)
+}
+
+func (l *routedInput) TouchPressDuration(id ebiten.TouchID) int {
+	return l.src.TouchPressDuration(id)
+}
+
+func (l *routedInput) IsFocused() bool {
+	return isFocused(l.src)
+}
+
+// consumeTouch excludes the touch with the given id from gesture recognition, as it's being
+// handed to another layer.
+func (tt *TouchTracker) consumeTouch(id ebiten.TouchID) {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	if t, ok := tt.touches[id]; ok {
+		t.consumed = true
+	}
+}
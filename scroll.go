@@ -0,0 +1,70 @@
+package ebiten_touchutils
+
+// ScrollState turns pan or drag movement into the scroll offset of content larger than its viewport,
+// like a list, clamped so it doesn't scroll past the content bounds.
+//
+// The offset is how far, in pixels, the content is scrolled from its top-left corner, between 0
+// and MaxX, MaxY. Moving the fingers down or right scrolls towards the top-left, as the content
+// follows the fingers.
+type ScrollState struct {
+	OffsetX, OffsetY float64
+	MaxX, MaxY       float64
+
+	// RubberBand is the fraction of the movement past the bounds that's still applied, in [0, 1],
+	// to let the content over-scroll and be pulled back with Settle. Zero clamps to the bounds.
+	RubberBand float64
+}
+
+// NewScrollState creates a ScrollState for content of the given size shown in a viewport of
+// the given size, scrolled to the top-left corner.
+func NewScrollState(contentW, contentH, viewportW, viewportH float64) ScrollState {
+	return ScrollState{
+		MaxX: max(contentW-viewportW, 0),
+		MaxY: max(contentH-viewportH, 0),
+	}
+}
+
+// Scroll moves the content by (dx, dy) pixels, as when the fingers move by that much.
+func (s *ScrollState) Scroll(dx, dy float64) {
+	s.OffsetX = s.scrollAxis(s.OffsetX, -dx, s.MaxX)
+	s.OffsetY = s.scrollAxis(s.OffsetY, -dy, s.MaxY)
+}
+
+// ApplyPan scrolls by the movement of p since the previous update frame. Call it on every update
+// frame a pan is being made, including while it coasts with WithMomentum.
+func (s *ScrollState) ApplyPan(p TwoFingerPan) {
+	dx, dy := p.FrameDelta()
+	s.Scroll(float64(dx), float64(dy))
+}
+
+// ApplyDrag scrolls by the movement of d since the previous update frame. Call it on every update
+// frame a drag is being made.
+func (s *ScrollState) ApplyDrag(d Drag) {
+	s.Scroll(float64(d.DeltaX), float64(d.DeltaY))
+}
+
+// Settle brings an over-scrolled offset back within the bounds. Call it once the fingers are released.
+func (s *ScrollState) Settle() {
+	s.OffsetX = min(max(s.OffsetX, 0), s.MaxX)
+	s.OffsetY = min(max(s.OffsetY, 0), s.MaxY)
+}
+
+// Offset returns the scroll offset clamped to the bounds, ignoring any over-scroll.
+func (s ScrollState) Offset() (float64, float64) {
+	return min(max(s.OffsetX, 0), s.MaxX), min(max(s.OffsetY, 0), s.MaxY)
+}
+
+// scrollAxis moves offset by delta on one axis, damping the movement past [0, limit] by the rubber band.
+func (s *ScrollState) scrollAxis(offset, delta, limit float64) float64 {
+	band := min(max(s.RubberBand, 0), 1)
+	switch next := offset + delta; {
+	case delta < 0 && next < 0:
+		inside := max(min(offset, -delta), 0)
+		return offset - inside + (delta+inside)*band
+	case delta > 0 && next > limit:
+		inside := max(min(limit-offset, delta), 0)
+		return offset + inside + (delta-inside)*band
+	default:
+		return next
+	}
+}
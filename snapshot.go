@@ -0,0 +1,250 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// TrackerState is a serializable snapshot of the state of a TouchTracker: its touches,
+// the taps of the last update frame and the multi finger gestures in progress.
+type TrackerState struct {
+	Frame    int                  `json:"frame"`
+	Touches  []TouchState         `json:"touches"`
+	Taps     []TapState           `json:"taps,omitempty"`
+	Pinch    *PinchState          `json:"pinch,omitempty"`
+	Pan      *TwoFingerPanState   `json:"pan,omitempty"`
+	ThreePan *ThreeFingerPanState `json:"threePan,omitempty"`
+	Paused   bool                 `json:"paused,omitempty"`
+}
+
+// TouchState is the state of a touch in a TrackerState.
+type TouchState struct {
+	ID         ebiten.TouchID `json:"id"`
+	OriginX    int            `json:"originX"`
+	OriginY    int            `json:"originY"`
+	CurrX      int            `json:"currX"`
+	CurrY      int            `json:"currY"`
+	PrevX      int            `json:"prevX"`
+	PrevY      int            `json:"prevY"`
+	Duration   int            `json:"duration"`
+	PressFrame int            `json:"pressFrame"`
+	Gestures   []GestureKind  `json:"gestures,omitempty"`
+	Consumed   bool           `json:"consumed,omitempty"`
+}
+
+// TapState is a tap of the last update frame in a TrackerState.
+type TapState struct {
+	ID       ebiten.TouchID `json:"id"`
+	X        int            `json:"x"`
+	Y        int            `json:"y"`
+	OriginX  int            `json:"originX"`
+	OriginY  int            `json:"originY"`
+	Force    float64        `json:"force,omitempty"`
+	Duration int            `json:"duration"`
+	Movement float64        `json:"movement"`
+}
+
+// PinchState is a pinch in progress in a TrackerState.
+type PinchState struct {
+	ID1            ebiten.TouchID `json:"id1"`
+	ID2            ebiten.TouchID `json:"id2"`
+	OriginDistance float64        `json:"originDistance"`
+	Distance       float64        `json:"distance"`
+	PrevDistance   float64        `json:"prevDistance"`
+	CenterX        int            `json:"centerX"`
+	CenterY        int            `json:"centerY"`
+	OriginCenterX  int            `json:"originCenterX"`
+	OriginCenterY  int            `json:"originCenterY"`
+	OriginAngle    float64        `json:"originAngle"`
+	Angle          float64        `json:"angle"`
+}
+
+// TwoFingerPanState is a two finger pan in progress in a TrackerState.
+//
+// A pan coasting with WithMomentum has no fingers left, and keeps moving from its momentum
+// position with its momentum velocity.
+type TwoFingerPanState struct {
+	ID1        ebiten.TouchID `json:"id1"`
+	ID2        ebiten.TouchID `json:"id2"`
+	OriginX    int            `json:"originX"`
+	OriginY    int            `json:"originY"`
+	LastX      int            `json:"lastX"`
+	LastY      int            `json:"lastY"`
+	PrevX      int            `json:"prevX"`
+	PrevY      int            `json:"prevY"`
+	Horizontal bool           `json:"horizontal"`
+
+	Coasting  bool    `json:"coasting,omitempty"`
+	MomentumX float64 `json:"momentumX,omitempty"`
+	MomentumY float64 `json:"momentumY,omitempty"`
+	VelocityX float64 `json:"velocityX,omitempty"`
+	VelocityY float64 `json:"velocityY,omitempty"`
+}
+
+// ThreeFingerPanState is a three finger pan in progress in a TrackerState.
+type ThreeFingerPanState struct {
+	ID1        ebiten.TouchID `json:"id1"`
+	ID2        ebiten.TouchID `json:"id2"`
+	ID3        ebiten.TouchID `json:"id3"`
+	OriginX    int            `json:"originX"`
+	OriginY    int            `json:"originY"`
+	LastX      int            `json:"lastX"`
+	LastY      int            `json:"lastY"`
+	Horizontal bool           `json:"horizontal"`
+}
+
+// Snapshot returns the current state of the tracker, to be stored and restored with LoadSnapshot,
+// like for deterministic replays together with WithInputSource.
+//
+// Coordinates are in screen space, regardless of WithCoordinateTransform.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Snapshot() TrackerState {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+
+	s := TrackerState{Frame: tt.frame, Paused: tt.paused}
+	s.Touches = make([]TouchState, 0, len(tt.touchIDs))
+	for _, id := range tt.touchIDs {
+		t, ok := tt.touches[id]
+		if !ok {
+			continue
+		}
+		ts := TouchState{
+			ID:         id,
+			OriginX:    t.originX,
+			OriginY:    t.originY,
+			CurrX:      t.currX,
+			CurrY:      t.currY,
+			PrevX:      t.prevX,
+			PrevY:      t.prevY,
+			Duration:   t.duration,
+			PressFrame: t.pressFrame,
+			Consumed:   t.consumed,
+		}
+		for k := GestureNone; k < 32; k++ {
+			if t.gestures.has(k) {
+				ts.Gestures = append(ts.Gestures, k)
+			}
+		}
+		s.Touches = append(s.Touches, ts)
+	}
+	for _, tap := range tt.taps {
+		s.Taps = append(s.Taps, TapState{ID: tap.id, X: tap.X, Y: tap.Y, OriginX: tap.originX, OriginY: tap.originY, Force: tap.force, Duration: tap.Duration, Movement: tap.Movement})
+	}
+	if p := tt.pinch; p != nil {
+		s.Pinch = &PinchState{
+			ID1: p.ID1, ID2: p.ID2,
+			OriginDistance: p.OriginDistance, Distance: p.Distance, PrevDistance: p.PrevDistance,
+			CenterX: p.CenterX, CenterY: p.CenterY,
+			OriginCenterX: p.OriginCenterX, OriginCenterY: p.OriginCenterY,
+			OriginAngle: p.OriginAngle, Angle: p.Angle,
+		}
+	}
+	if p := tt.pan; p != nil {
+		s.Pan = &TwoFingerPanState{
+			ID1: p.ID1, ID2: p.ID2,
+			OriginX: p.OriginX, OriginY: p.OriginY,
+			LastX: p.LastX, LastY: p.LastY,
+			PrevX: p.PrevX, PrevY: p.PrevY,
+			Horizontal: p.isHorizontal,
+			Coasting:   p.coasting,
+		}
+		if m := tt.momentum; m != nil {
+			s.Pan.MomentumX, s.Pan.MomentumY = m.x, m.y
+			s.Pan.VelocityX, s.Pan.VelocityY = m.vx, m.vy
+		}
+	}
+	if p := tt.threePan; p != nil {
+		s.ThreePan = &ThreeFingerPanState{
+			ID1: p.ID1, ID2: p.ID2, ID3: p.ID3,
+			OriginX: p.OriginX, OriginY: p.OriginY,
+			LastX: p.LastX, LastY: p.LastY,
+			Horizontal: p.isHorizontal,
+		}
+	}
+	return s
+}
+
+// LoadSnapshot replaces the state of the tracker with s, as returned by Snapshot.
+// Configuration and callbacks are kept, and gestures not in s are dropped as on Reset,
+// except that the touches in s are tracked rather than ignored.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) LoadSnapshot(s TrackerState) {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+
+	tt.resetState()
+	tt.frame = s.Frame
+	tt.paused = s.Paused
+	clear(tt.ignored)
+	for _, ts := range s.Touches {
+		t := &touch{
+			originX: ts.OriginX, originY: ts.OriginY,
+			currX: ts.CurrX, currY: ts.CurrY,
+			prevX: ts.PrevX, prevY: ts.PrevY,
+			duration:   ts.Duration,
+			pressFrame: ts.PressFrame,
+			pressedAt:  tt.clock,
+			consumed:   ts.Consumed,
+		}
+		for _, k := range ts.Gestures {
+			t.gestures.add(k)
+		}
+		tt.touches[ts.ID] = t
+		tt.touchIDs = append(tt.touchIDs, ts.ID)
+	}
+	for _, tap := range s.Taps {
+		tt.taps = append(tt.taps, tapEvent{Tap: Tap{X: tap.X, Y: tap.Y, Duration: tap.Duration, Movement: tap.Movement}, id: tap.ID, originX: tap.OriginX, originY: tap.OriginY, frame: s.Frame, force: tap.Force})
+	}
+
+	// Gestures are only restored with the touches making them, so a partial or edited snapshot
+	// doesn't leave one following fingers that aren't tracked.
+	if p := s.Pinch; p != nil && tt.tracks(p.ID1, p.ID2) {
+		t1, t2 := tt.touches[p.ID1], tt.touches[p.ID2]
+		tt.pinch = &Pinch{
+			ID1: p.ID1, ID2: p.ID2,
+			OriginDistance: p.OriginDistance, Distance: p.Distance, PrevDistance: p.PrevDistance,
+			CenterX: p.CenterX, CenterY: p.CenterY,
+			OriginCenterX: p.OriginCenterX, OriginCenterY: p.OriginCenterY,
+			OriginAngle: p.OriginAngle, Angle: p.Angle,
+			x1: t1.currX, y1: t1.currY,
+			x2: t2.currX, y2: t2.currY,
+			deadzone:          tt.pinchDeadzone,
+			rotationThreshold: tt.rotationThreshold,
+		}
+	}
+	if p := s.Pan; p != nil && (p.Coasting || tt.tracks(p.ID1, p.ID2)) {
+		tt.pan = &TwoFingerPan{
+			ID1: p.ID1, ID2: p.ID2,
+			OriginX: p.OriginX, OriginY: p.OriginY,
+			LastX: p.LastX, LastY: p.LastY,
+			PrevX: p.PrevX, PrevY: p.PrevY,
+			polledX: p.PrevX, polledY: p.PrevY,
+			isHorizontal: p.Horizontal,
+			threshold:    tt.panThreshold,
+			coasting:     p.Coasting,
+		}
+		if p.Coasting {
+			tt.momentum = &momentum{x: p.MomentumX, y: p.MomentumY, vx: p.VelocityX, vy: p.VelocityY}
+		}
+	}
+	if p := s.ThreePan; p != nil && tt.tracks(p.ID1, p.ID2, p.ID3) {
+		tt.threePan = &ThreeFingerPan{
+			ID1: p.ID1, ID2: p.ID2, ID3: p.ID3,
+			OriginX: p.OriginX, OriginY: p.OriginY,
+			LastX: p.LastX, LastY: p.LastY,
+			isHorizontal: p.Horizontal,
+		}
+	}
+}
+
+// tracks returns if every touch with the given ids is tracked.
+func (tt *TouchTracker) tracks(ids ...ebiten.TouchID) bool {
+	for _, id := range ids {
+		if _, ok := tt.touches[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// Split is the orientation of the line dividing the screen in two halves.
+type Split int
+
+const (
+	// SplitVertical divides the screen with a vertical line into left and right halves.
+	SplitVertical Split = iota
+	// SplitHorizontal divides the screen with a horizontal line into top and bottom halves.
+	SplitHorizontal
+)
+
+// SplitTap is a pair of taps made on both halves of the screen at roughly the same time,
+// as in two players sharing one device.
+type SplitTap struct {
+	// First is the tap made on the left half for SplitVertical, or the top half for SplitHorizontal.
+	First Tap
+	// Second is the tap made on the right half for SplitVertical, or the bottom half for SplitHorizontal.
+	Second Tap
+}
+
+// half returns 0 if the tap is on the first half of the split and 1 if it's on the second one.
+// Taps exactly on the line belong to the second half.
+func (s Split) half(tap Tap, line int) int {
+	v := tap.X
+	if s == SplitHorizontal {
+		v = tap.Y
+	}
+	if v < line {
+		return 0
+	}
+	return 1
+}
+
+// SplitTapped returns the taps made on each half of the screen if both halves were tapped
+// within windowFrames of each other, and one of those taps was made in the last update frame.
+//
+// line is the X coordinate dividing the halves for SplitVertical, and the Y coordinate for SplitHorizontal.
+// It's in the same coordinates as the reported taps, with WithFlipX, WithFlipY and
+// WithCoordinateTransform applied, so the halves match the tap positions.
+// If a half was tapped more than once in the window, the latest tap is reported.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) SplitTapped(split Split, line int, windowFrames int) (SplitTap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.handled.has(GestureTap) {
+		return SplitTap{}, false
+	}
+
+	var found [2]*tapEvent
+	var taps [2]Tap
+	for i := len(tt.tapHistory) - 1; i >= 0; i-- {
+		ev := &tt.tapHistory[i]
+		if tt.frame-ev.frame > windowFrames {
+			break
+		}
+		tap := tt.worldTap(ev.Tap)
+		h := split.half(tap, line)
+		if found[h] == nil {
+			found[h], taps[h] = ev, tap
+		}
+	}
+
+	first, second := found[0], found[1]
+	if first == nil || second == nil {
+		return SplitTap{}, false
+	}
+	if first.frame != tt.frame && second.frame != tt.frame {
+		return SplitTap{}, false
+	}
+	return SplitTap{First: taps[0], Second: taps[1]}, true
+}
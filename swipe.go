@@ -0,0 +1,70 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// WithSwipeMinDistance sets the minimum distance, in pixels, a finger must travel to be a swipe.
+// Defaults to 30.
+func WithSwipeMinDistance(px float64) Option {
+	return func(tt *TouchTracker) {
+		tt.swipeMinDistance = px
+	}
+}
+
+// WithSwipeMaxDuration sets the maximum amount of frames a finger can be touching the screen
+// to be a swipe, rather than a slow drag. Defaults to 20.
+func WithSwipeMaxDuration(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.swipeMaxDuration = frames
+	}
+}
+
+// releaseSwipe checks if touch t was swiped when released, and records it if so.
+func (tt *TouchTracker) releaseSwipe(t *touch) {
+	if tt.swipe != nil || t.consumed || tt.suppressed(GestureSwipe, t) {
+		return
+	}
+	if t.duration > tt.swipeMaxDuration || distance2d(t.originX, t.originY, t.currX, t.currY) < tt.swipeMinDistance {
+		return
+	}
+
+	dx, dy := float64(t.currX-t.originX), float64(t.currY-t.originY)
+	frames := float64(max(t.duration, 1))
+
+	// Take the direction from the latest velocity, which is less noisy than the last frame
+	// alone and follows the intent of the flick better than the whole displacement.
+	dir := directionOf(dx, dy)
+	if vx, vy, ok := t.velocities.smoothed(tt.flickSampleWindow); ok && (vx != 0 || vy != 0) {
+		dir = directionOf(vx, vy)
+	}
+
+	t.gestures.add(GestureSwipe)
+	tt.record(GestureSwipe, t.currX, t.currY)
+	vsx, vsy := tt.averagePerSecond(t, dx, dy)
+	tt.swipe = &Swipe{
+		StartX:    t.originX,
+		StartY:    t.originY,
+		EndX:      t.currX,
+		EndY:      t.currY,
+		Direction: dir,
+		VelocityX: dx / frames,
+		VelocityY: dy / frames,
+
+		VelocityPerSecondX: vsx,
+		VelocityPerSecondY: vsy,
+	}
+}
+
+// Swipe returns the Swipe data if a one finger swipe was made (released) in the last update frame.
+//
+// A swipe must travel at least the swipe minimum distance within the swipe maximum duration.
+// Swipes are not recorded as taps.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Swipe() (Swipe, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.swipe != nil && !tt.handled.has(GestureSwipe) {
+		return tt.worldSwipe(*tt.swipe), true
+	}
+	return Swipe{}, false
+}
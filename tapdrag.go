@@ -0,0 +1,47 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// TapDrag is the gesture of tapping once and then pressing again in the same place and dragging
+// the finger, like the one finger zoom of maps.
+type TapDrag struct {
+	// Tap is the tap made before the finger was pressed again.
+	Tap Tap
+
+	// Drag is the drag of the second press.
+	Drag
+}
+
+// afterTap returns the tap a finger pressed at (x, y) follows, if it could start a tap drag:
+// no other finger touches the screen, and it's within the double tap window and radius of a single tap.
+func (tt *TouchTracker) afterTap(x, y int) *Tap {
+	if len(tt.touches) > 0 || len(tt.tapChain) != 1 {
+		return nil
+	}
+	tap := tt.tapChain[0]
+	if tt.frame-tap.frame > tt.doubleTapWindow || distance2d(tap.X, tap.Y, x, y) > tt.doubleTapRadius {
+		return nil
+	}
+	return &tap.Tap
+}
+
+// TapDrag returns the latest TapDrag data if a finger pressed right after a tap is being dragged.
+//
+// A tap drag starts when the second press moves past the drag threshold, and lasts until released.
+// It's reported instead of a Drag, and the tap before it is neither a single tap nor the start of a
+// double tap. If the second press is released without moving, it's a double tap instead.
+//
+// TapDrag data updates every update frame.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TapDrag() (TapDrag, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.tapDrag != nil && !tt.handled.has(GestureTapDrag) {
+		td := *tt.tapDrag
+		td.Tap = tt.worldTap(td.Tap)
+		td.Drag = tt.worldDrag(td.Drag)
+		return td, true
+	}
+	return TapDrag{}, false
+}
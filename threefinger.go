@@ -0,0 +1,79 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "math"
+
+// updateThreeFingerPan recognizes a pan while three fingers touch the screen, and follows it
+// until one of them is released.
+func (tt *TouchTracker) updateThreeFingerPan() {
+	if tt.threePan != nil {
+		t1, t2, t3 := tt.touches[tt.threePan.ID1], tt.touches[tt.threePan.ID2], tt.touches[tt.threePan.ID3]
+		tt.threePan.LastX = (t1.currX + t2.currX + t3.currX) / 3
+		tt.threePan.LastY = (t1.currY + t2.currY + t3.currY) / 3
+		return
+	}
+
+	id1, id2, id3 := tt.touchIDs[0], tt.touchIDs[1], tt.touchIDs[2]
+	t1, t2, t3 := tt.touches[id1], tt.touches[id2], tt.touches[id3]
+	if t1.consumed || t2.consumed || t3.consumed {
+		return
+	}
+
+	// The centroid must move past the pan threshold along one axis.
+	diffX := float64(t1.currX+t2.currX+t3.currX-t1.originX-t2.originX-t3.originX) / 3
+	diffY := float64(t1.currY+t2.currY+t3.currY-t1.originY-t2.originY-t3.originY) / 3
+	horizontal := math.Abs(diffX) > math.Abs(diffY)
+	if max(math.Abs(diffX), math.Abs(diffY)) <= tt.panThreshold {
+		return
+	}
+
+	// Every finger must move the same way as the centroid by at least half the threshold, so
+	// pinching with two of the fingers while the third stays still isn't taken for a pan.
+	dir := directionOf(diffX, diffY)
+	for _, t := range []*touch{t1, t2, t3} {
+		dx, dy := float64(t.currX-t.originX), float64(t.currY-t.originY)
+		moved := math.Abs(dy)
+		if horizontal {
+			moved = math.Abs(dx)
+		}
+		if directionOf(dx, dy) != dir || moved < tt.panThreshold/2 {
+			return
+		}
+	}
+
+	if tt.suppressed(GestureThreeFingerPan, t1, t2, t3) {
+		return
+	}
+	t1.gestures.add(GestureThreeFingerPan)
+	t2.gestures.add(GestureThreeFingerPan)
+	t3.gestures.add(GestureThreeFingerPan)
+	tt.record(GestureThreeFingerPan, (t1.currX+t2.currX+t3.currX)/3, (t1.currY+t2.currY+t3.currY)/3)
+	tt.threePan = &ThreeFingerPan{
+		ID1:          id1,
+		ID2:          id2,
+		ID3:          id3,
+		OriginX:      (t1.originX + t2.originX + t3.originX) / 3,
+		OriginY:      (t1.originY + t2.originY + t3.originY) / 3,
+		LastX:        (t1.currX + t2.currX + t3.currX) / 3,
+		LastY:        (t1.currY + t2.currY + t3.currY) / 3,
+		isHorizontal: horizontal,
+	}
+}
+
+// ThreeFingerPan returns the latest ThreeFingerPan data if a three finger pan gesture is being made.
+//
+// The pan is recognized when all three fingers move the same way past the pan threshold, and lasts
+// until one of them is released.
+//
+// ThreeFingerPan data updates every update frame.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ThreeFingerPan() (ThreeFingerPan, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.threePan != nil && !tt.handled.has(GestureThreeFingerPan) {
+		return tt.worldThreeFingerPan(*tt.threePan), true
+	}
+	return ThreeFingerPan{}, false
+}
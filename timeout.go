@@ -0,0 +1,60 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// WithTouchTimeout drops a touch from tracking once it stays in the same position for the given
+// amount of frames without being released, ending any gesture it takes part in. It guards against
+// platforms that occasionally don't report the release of a finger sliding off the screen.
+//
+// A dropped touch is ignored until it's actually released. Note that a finger held perfectly still,
+// like for a long press, is dropped too, so the timeout should be well above the long press duration.
+// Defaults to 0, which never drops touches.
+func WithTouchTimeout(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.touchTimeout = frames
+	}
+}
+
+// dropStaleTouches stops tracking the touches that stayed still for longer than the touch timeout.
+func (tt *TouchTracker) dropStaleTouches() {
+	if tt.touchTimeout <= 0 {
+		return
+	}
+	for id, t := range tt.touches {
+		// Touches no longer reported by the input source don't move either.
+		if !slices.Contains(tt.touchIDs, id) {
+			t.stillFrames++
+		}
+		if t.stillFrames < tt.touchTimeout {
+			continue
+		}
+		tt.endGesturesOf(id)
+		tt.ignored[id] = struct{}{}
+		delete(tt.touches, id)
+		tt.touchIDs = slices.DeleteFunc(tt.touchIDs, func(other ebiten.TouchID) bool {
+			return other == id
+		})
+	}
+}
+
+// endGesturesOf cancels the gestures in progress touch id takes part in.
+func (tt *TouchTracker) endGesturesOf(id ebiten.TouchID) {
+	if tt.pinch != nil && (id == tt.pinch.ID1 || id == tt.pinch.ID2) {
+		tt.pinch = nil
+	}
+	if tt.pan != nil && !tt.pan.coasting && (id == tt.pan.ID1 || id == tt.pan.ID2) {
+		tt.pan = nil
+	}
+	if tt.threePan != nil && (id == tt.threePan.ID1 || id == tt.threePan.ID2 || id == tt.threePan.ID3) {
+		tt.threePan = nil
+	}
+	if tt.dragging && id == tt.dragID {
+		tt.dragging = false
+	}
+}
@@ -0,0 +1,79 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TouchType is the kind of pointer behind a touch.
+type TouchType int
+
+const (
+	// TypeUnknown is reported when the platform can't tell what made the touch.
+	// It's treated as a finger.
+	TypeUnknown TouchType = iota
+	TypeFinger
+	TypeStylus
+)
+
+// TouchTypeProvider reports what made each touch on platforms that expose it.
+//
+// Ebiten doesn't surface the touch type, so it must be plugged in by the user
+// with WithTouchTypeProvider.
+type TouchTypeProvider interface {
+	// TouchType returns the type of the touch, or TypeUnknown if it's not available.
+	TouchType(id ebiten.TouchID) TouchType
+}
+
+// WithTouchTypeProvider sets the source of the touch types reported in TouchInfo
+// and used by WithIgnoreTouchType.
+//
+// Without a provider every touch is TypeUnknown.
+func WithTouchTypeProvider(p TouchTypeProvider) Option {
+	return func(tt *TouchTracker) {
+		tt.touchTypeProvider = p
+	}
+}
+
+// WithIgnoreTouchType makes the tracker ignore touches of the given types, as if they never
+// happened, for example to leave stylus strokes to a drawing canvas while fingers navigate.
+// Ignoring TypeFinger also ignores TypeUnknown touches.
+func WithIgnoreTouchType(types ...TouchType) Option {
+	return func(tt *TouchTracker) {
+		tt.ignoredTypes = append(tt.ignoredTypes, types...)
+	}
+}
+
+// touchType returns the type of the touch with the given id, as reported by the provider.
+func (tt *TouchTracker) touchType(id ebiten.TouchID) TouchType {
+	if tt.touchTypeProvider == nil {
+		return TypeUnknown
+	}
+	return tt.touchTypeProvider.TouchType(id)
+}
+
+// ignoresType returns if the touch with the given id is of a type ignored with WithIgnoreTouchType.
+func (tt *TouchTracker) ignoresType(id ebiten.TouchID) bool {
+	if len(tt.ignoredTypes) == 0 {
+		return false
+	}
+	typ := tt.touchType(id)
+	if typ == TypeUnknown {
+		typ = TypeFinger
+	}
+	return slices.Contains(tt.ignoredTypes, typ)
+}
+
+// ignoreUntracked marks the untracked touches in ids of an ignored type as ignored, so touches
+// that show up without being reported as just pressed are filtered like the rest.
+func (tt *TouchTracker) ignoreUntracked(ids []ebiten.TouchID) []ebiten.TouchID {
+	for _, id := range ids {
+		if _, ok := tt.touches[id]; !ok && tt.ignoresType(id) {
+			tt.ignored[id] = struct{}{}
+		}
+	}
+	return ids
+}
@@ -1,11 +1,15 @@
+//go:build !touchutils_headless
+
 package ebiten_touchutils
 
 import (
+	"image"
 	"math"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 // distance between points a and b in 1d space.
@@ -23,69 +27,285 @@ func distance2d(xa, ya, xb, yb int) float64 {
 type touch struct {
 	originX, originY int
 	currX, currY     int
+	prevX, prevY     int
 	duration         int
-	isPinch, isPan   bool
-}
 
-// Pinch is the gesture of moving two fingers closer or farther away from each other.
-type Pinch struct {
-	ID1, ID2 ebiten.TouchID
+	// pressedAt is when the touch was first seen, as reported by the tracker clock,
+	// and pressFrame the update frame it was first seen in.
+	pressedAt  time.Time
+	pressFrame int
 
-	OriginDistance float64
-	Distance       float64
+	// gestures holds every kind of gesture the touch took part in.
+	gestures gestureSet
 
-	CenterX, CenterY int
-}
+	// consumed touches are tracked but excluded from gesture recognition.
+	consumed bool
 
-func (p Pinch) IsInward() bool {
-	return p.OriginDistance > p.Distance
-}
+	velocities velocityRing
 
-func (p Pinch) IsOutward() bool {
-	return p.OriginDistance < p.Distance
-}
+	// vx, vy is the smoothed velocity of the touch, in pixels per frame.
+	vx, vy float64
 
-// TwoFingerPan is the gesture of moving two fingers across the screen
-// either vertically or horizontally, without much change in the distance between the fingers.
-type TwoFingerPan struct {
-	ID1, ID2 ebiten.TouchID
+	// stillFrames is for how many frames the touch has stayed in the same position.
+	stillFrames int
 
-	LastX, LastY     int
-	OriginX, OriginY int
+	force    float64
+	hasForce bool
 
-	isHorizontal bool
-}
+	// peakForce is the highest force the touch was pressed with.
+	peakForce float64
 
-func (p TwoFingerPan) IsHorizontal() bool {
-	return p.isHorizontal
-}
+	// afterTap is the tap the touch was pressed right after, if it can start a tap drag.
+	afterTap *Tap
+
+	// dragged is set once the touch moves past the drag threshold, regardless of other fingers.
+	dragged bool
 
-func (p TwoFingerPan) IsVertical() bool {
-	return !p.isHorizontal
+	typ TouchType
 }
 
-// Tap is the action of pressing and releasing one touch in the screen
-// in a short time and without much movement.
-type Tap struct {
-	X, Y int
+// maxTapHistory is the amount of taps remembered across frames.
+const maxTapHistory = 16
+
+// tapEvent is a Tap along with where the touch started, the frame it was recorded in
+// and the highest force it was pressed with.
+type tapEvent struct {
+	Tap
+	id               ebiten.TouchID
+	originX, originY int
+	frame            int
+	force            float64
 }
 
+var _ Tracker = (*TouchTracker)(nil)
+
 type TouchTracker struct {
-	touchIDs []ebiten.TouchID
-	touches  map[ebiten.TouchID]*touch
+	input InputSource
+	opts  []Option
+
+	mouseEmulation bool
+
+	// touchIDs holds every active touch as of the last frame, ordered by ID, and justPressed
+	// the ones pressed in it. They are kept apart so code between reading one and the other
+	// always sees what its name says.
+	touchIDs    []ebiten.TouchID
+	justPressed []ebiten.TouchID
+	touches     map[ebiten.TouchID]*touch
+
+	// pressed and released hold the touches pressed and released in the last frame.
+	pressed  []TouchPoint
+	released []TouchPoint
+
+	// ignored holds the touches that were active on Reset, until they are released.
+	ignored  map[ebiten.TouchID]struct{}
 	pinch    *Pinch
 	pan      *TwoFingerPan
-	taps     []Tap
+	threePan *ThreeFingerPan
+	taps     []tapEvent
+
+	// handled holds the gestures consumed in the last frame, including the disabled ones.
+	handled gestureSet
+
+	// disabled holds the gestures turned off with EnableGesture.
+	disabled gestureSet
+
+	// started holds the pinch, pan and long press gestures that became active in the last frame.
+	started gestureSet
+
+	// pinchEnded and panEnded hold the final state of the gestures that ended in the last frame.
+	pinchEnded *Pinch
+	panEnded   *TwoFingerPan
+
+	// tapHistory holds the latest taps across frames, oldest first.
+	tapHistory []tapEvent
+
+	history     []GestureEvent
+	historySize int
+
+	// frame is the number of Update calls made so far.
+	frame int
+
+	// now is the time source, and clock the time it reported for the current frame.
+	now   func() time.Time
+	clock time.Time
+
+	// frameTime is how long the last update frame lasted by the clock.
+	frameTime time.Duration
+
+	// allReleased is set on the frame the last active touch was released.
+	allReleased bool
+
+	// pinchEndFrame is the frame the latest pinch concluded, 0 if none did.
+	pinchEndFrame int
+
+	suppressedBy map[GestureKind]gestureSet
+	priority     []GestureKind
+
+	// gracePeriod is set with WithGestureGracePeriod, and lost is the finger of the pinch
+	// or pan in progress released within it.
+	gracePeriod int
+	lost        *lostTouch
+
+	tapMaxDuration int
+	tapMaxTime     time.Duration
+	tapMaxMovement float64
+	pinchThreshold float64
+	panThreshold   float64
+	panActivation  float64
+
+	// pinchFrames is for how many consecutive frames two fingers have been a pinch candidate.
+	pinchFrames    int
+	pinchMinFrames int
+
+	minPinchDistance   float64
+	pinchDeadzone      float64
+	rotationThreshold  float64
+	flickSampleWindow  int
+	velocitySmoothing  float64
+	flickMinVelocity   float64
+	thumbTapSeparation int
+
+	forceProvider            ForceProvider
+	touchTypeProvider        TouchTypeProvider
+	ignoredTypes             []TouchType
+	forceThreshold           float64
+	forceTapThreshold        float64
+	forcePressed             bool
+	forcePressX, forcePressY int
+
+	// transform converts the coordinates reported for gestures, if set.
+	transform func(x, y int) (int, int)
+
+	// momentum is the coasting state of pan after its fingers were released.
+	momentum         *momentum
+	momentumFriction float64
+
+	ambiguity float64
+
+	flick *flick
+
+	flingVelocity          float64
+	flingVelocityPerSecond float64
+	fling                  *Fling
+
+	swipeMinDistance float64
+	swipeMaxDuration int
+	swipe            *Swipe
+
+	screenW, screenH int
+	edgeSwipeMargin  int
+	palmRejection    bool
+	touchTimeout     int
+
+	doubleTapWindow int
+	doubleTapRadius float64
+	tapChain        []tapEvent
+	doubleTap       *Tap
+	tripleTap       *Tap
+	singleTap       *Tap
+
+	dragThreshold float64
+	dragging      bool
+	dragID        ebiten.TouchID
+	drag          *Drag
+	tapDrag       *TapDrag
+
+	longPressDuration int
+	tapLongPressGap   TapLongPressGap
+	longPressTime     time.Duration
+	longPressRadius   float64
+	longPress         *LongPress
+	longPressing      bool
+	twoFingerHold     *TwoFingerHold
+
+	// frameGestures backs the gestures reported anew on every update frame, so following
+	// a held or dragged finger doesn't allocate.
+	frameGestures struct {
+		drag          Drag
+		tapDrag       TapDrag
+		longPress     LongPress
+		twoFingerHold TwoFingerHold
+	}
+
+	focusSuppression int
+	wasFocused       bool
+	refocusFrame     int
+
+	paused     bool
+	pauseInput PauseInput
+	queuedTap  *tapEvent
+
+	// pendingTaps holds released taps waiting for companion fingers of a multi finger tap.
+	pendingTaps    []tapEvent
+	pendingSince   int
+	multiTapWindow int
+	tapDebounce    int
+
+	flipX, flipY          bool
+	flipWidth, flipHeight int
+
+	// soloTaps holds taps split from a group for being too far apart, reported one per frame.
+	soloTaps          []tapEvent
+	multiTapMaxSpread float64
+
+	onTap       handlers[Tap]
+	onDoubleTap handlers[Tap]
+	onPinch     handlers[Pinch]
+	onPan       handlers[TwoFingerPan]
+
+	// onSwipe holds the swipe callbacks for each direction, and for any direction at DirNone.
+	onSwipe [DirRight + 1]handlers[Swipe]
 
 	m sync.RWMutex
 }
 
-func NewTouchTracker() *TouchTracker {
-	return &TouchTracker{
-		touchIDs: make([]ebiten.TouchID, 0),
-		taps:     make([]Tap, 0),
-		touches:  make(map[ebiten.TouchID]*touch),
+// NewTouchTracker creates a TouchTracker configured with the given options.
+func NewTouchTracker(opts ...Option) *TouchTracker {
+	tt := &TouchTracker{
+		input: ebitenInput{},
+		opts:  opts,
+		now:   time.Now,
+
+		touchIDs:   make([]ebiten.TouchID, 0),
+		taps:       make([]tapEvent, 0),
+		tapHistory: make([]tapEvent, 0, maxTapHistory),
+		touches:    make(map[ebiten.TouchID]*touch),
+		ignored:    make(map[ebiten.TouchID]struct{}),
+
+		tapMaxDuration:     30,
+		tapMaxMovement:     2,
+		pinchThreshold:     10,
+		pinchMinFrames:     2,
+		panThreshold:       10,
+		flickSampleWindow:  5,
+		pinchDeadzone:      1,
+		rotationThreshold:  0.26,
+		flickMinVelocity:   5,
+		thumbTapSeparation: 100,
+		forceThreshold:     0.75,
+		forceTapThreshold:  0.5,
+		focusSuppression:   3,
+		swipeMinDistance:   30,
+		swipeMaxDuration:   20,
+		edgeSwipeMargin:    20,
+		flingVelocity:      10,
+		multiTapWindow:     3,
+		velocitySmoothing:  0.5,
+		doubleTapWindow:    20,
+		doubleTapRadius:    20,
+		dragThreshold:      10,
+		longPressDuration:  30,
+		longPressRadius:    4,
+		wasFocused:         true,
+	}
+	tt.setSuppressionRules(DefaultSuppressionRules())
+	for _, opt := range opts {
+		opt(tt)
+	}
+	if tt.mouseEmulation {
+		tt.input = mouseInput{src: tt.input}
 	}
+	return tt
 }
 
 // Update must be called on every Update frame.
@@ -93,180 +313,569 @@ func NewTouchTracker() *TouchTracker {
 // Ideally this would behave like `inpututils` by hooking into ebiten
 // with `hook.AppendHookOnBeforeUpdate`. Sadly, altho reasonably, this behaviour is internal
 // so external libs must be called explicitly.
+//
+// Callbacks registered with the On methods are run at the end of Update.
 func (tt *TouchTracker) Update() {
+	tt.update()
+	tt.dispatch()
+}
+
+// update processes the touch input of the current frame.
+func (tt *TouchTracker) update() {
 	tt.m.Lock()
 	defer tt.m.Unlock()
 
-	// Clear the previous frame's taps.
+	tt.frame++
+	now := tt.now()
+	if !tt.clock.IsZero() {
+		tt.frameTime = now.Sub(tt.clock)
+	}
+	tt.clock = now
+	prevCount := len(tt.touchIDs)
+	wasLongPress := tt.longPress != nil
+
+	// Clear the previous frame's gestures.
 	tt.taps = tt.taps[:0]
+	tt.pressed = tt.pressed[:0]
+	tt.released = tt.released[:0]
+	tt.flick = nil
+	tt.fling = nil
+	tt.swipe = nil
+	tt.drag = nil
+	tt.tapDrag = nil
+	tt.longPress = nil
+	tt.longPressing = false
+	tt.twoFingerHold = nil
+	tt.pinchEnded = nil
+	tt.panEnded = nil
+	tt.handled = tt.disabled
+	tt.started = 0
+	tt.forcePressed = false
+	tt.ambiguity = 0
 
-	// Handle released touches in this frame
+	// Deliver the tap queued while paused.
+	if !tt.paused && tt.queuedTap != nil {
+		tt.addTap(*tt.queuedTap)
+		tt.queuedTap = nil
+		tt.pendingTaps = tt.pendingTaps[:0]
+		tt.pendingSince = 0
+		tt.soloTaps = nil
+	}
+
+	// Handle released touches in this frame, counting the touches from before any of them is
+	// deleted so fingers lifted together are treated the same whatever order they're visited in.
+	touching := len(tt.touches)
 	for id, t := range tt.touches {
-		if inpututil.IsTouchJustReleased(id) {
-			// clear pinch if part of it was released
-			if tt.pinch != nil && (id == tt.pinch.ID1 || id == tt.pinch.ID2) {
+		if tt.input.IsTouchJustReleased(id) {
+			tt.released = append(tt.released, TouchPoint{ID: id, X: t.currX, Y: t.currY})
+
+			// clear pinch if part of it was released, unless it waits for the finger to land again
+			lost := tt.keepLost(id, t)
+			if !lost && tt.pinch != nil && (id == tt.pinch.ID1 || id == tt.pinch.ID2) {
+				tt.pinchEnded = tt.pinch
 				tt.pinch = nil
+				tt.pinchEndFrame = tt.frame
+			}
+
+			// clear pan if part of it was released, unless it keeps coasting
+			if !lost && tt.pan != nil && !tt.pan.coasting && (id == tt.pan.ID1 || id == tt.pan.ID2) {
+				if !tt.startMomentum() {
+					tt.panEnded = tt.pan
+					tt.pan = nil
+				}
+			}
+
+			// clear three finger pan if part of it was released
+			if tt.threePan != nil && (id == tt.threePan.ID1 || id == tt.threePan.ID2 || id == tt.threePan.ID3) {
+				tt.threePan = nil
+			}
+
+			// stop following the drag, so a new finger reusing the ID doesn't carry it on
+			if tt.dragging && id == tt.dragID {
+				tt.dragging = false
 			}
 
-			// clear pan if part of it was released
-			if tt.pan != nil && (id == tt.pan.ID1 || id == tt.pan.ID2) {
-				tt.pan = nil
+			tt.rejectPalmRelease(t)
+			if !tt.paused {
+				tt.releaseFlick(t)
+				tt.releaseSwipe(t)
+				tt.releaseFling(t, touching)
 			}
 
-			// If this one has not been touched long (30 frames can be assumed
-			// to be 500ms), or moved far, then record tap.
+			// If this one has not been touched long (by default 30 frames, which can
+			// be assumed to be 500ms), and hasn't moved far, then record tap.
 			diff := distance2d(t.originX, t.originY, t.currX, t.currY)
-			if !t.isPinch && !t.isPan && (t.duration <= 30 || diff < 2) {
-				tt.taps = append(tt.taps, Tap{
-					X: t.currX,
-					Y: t.currY,
-				})
+			if !t.consumed && !tt.suppressed(GestureTap, t) && tt.isTapDuration(t) && diff < tt.tapMaxMovement {
+				tap := tapEvent{
+					Tap: Tap{
+						X:        t.currX,
+						Y:        t.currY,
+						Duration: t.duration,
+						Movement: diff,
+					},
+					id:      id,
+					originX: t.originX,
+					originY: t.originY,
+					force:   t.peakForce,
+				}
+				if tt.paused {
+					tt.queuedTap = &tap
+				} else if !tt.isBounce(tap) {
+					tt.pendingTaps = append(tt.pendingTaps, tap)
+				}
 			}
 
 			delete(tt.touches, id)
 		}
 	}
 
-	// Store new touches in this frame
-	tt.touchIDs = inpututil.AppendJustPressedTouchIDs(tt.touchIDs[:0])
-	for _, id := range tt.touchIDs {
-		x, y := ebiten.TouchPosition(id)
-		tt.touches[id] = &touch{
-			originX: x, originY: y,
-			currX: x, currY: y,
+	if !tt.paused {
+		tt.flushPendingTaps()
+		tt.updateDoubleTap()
+	}
+
+	// Store new touches in this frame, ignoring them while paused or
+	// right after regaining focus if configured so.
+	refocusing := tt.updateFocus()
+	tt.justPressed = tt.input.AppendJustPressedTouchIDs(tt.justPressed[:0])
+	slices.Sort(tt.justPressed)
+	for _, id := range tt.justPressed {
+		delete(tt.ignored, id)
+
+		// The ID may be reused before the release of its previous touch was seen,
+		// so the gestures of that touch end rather than pass to the new one.
+		if _, ok := tt.touches[id]; ok {
+			tt.endGesturesOf(id)
+			delete(tt.touches, id)
+		}
+		if tt.ignoresType(id) {
+			tt.ignored[id] = struct{}{}
+			continue
 		}
+		x, y := tt.input.TouchPosition(id)
+		tt.pressed = append(tt.pressed, TouchPoint{ID: id, X: x, Y: y})
+		tt.touches[id] = tt.newTouch(id, x, y, refocusing)
+		tt.resumeLost(id, tt.touches[id])
+	}
+	tt.updateLost()
+
+	// A new touch stops a coasting pan right away.
+	if len(tt.pressed) > 0 {
+		tt.stopMomentum()
 	}
+	tt.updateMomentum()
 
-	// Store all touchIDs (new and old) in this frame
-	tt.touchIDs = ebiten.AppendTouchIDs(tt.touchIDs[:0])
+	// Store all touchIDs (new and old) in this frame, sorted so the order doesn't depend
+	// on the order ebiten reports them in, which can change from frame to frame.
+	tt.touchIDs = tt.dropIgnored(tt.ignoreUntracked(tt.input.AppendTouchIDs(tt.touchIDs[:0])))
+	slices.Sort(tt.touchIDs)
 
 	// Update the current position and durations of any touches that have
 	// neither begun nor ended in this frame.
 	for _, id := range tt.touchIDs {
-		t := tt.touches[id]
-		t.duration = inpututil.TouchPressDuration(id)
-		t.currX, t.currY = ebiten.TouchPosition(id)
+		t, ok := tt.touches[id]
+		if !ok {
+			// Ebiten may report a touch as active without ever reporting it as just pressed,
+			// so it's tracked as pressed from the first frame it's seen.
+			x, y := tt.input.TouchPosition(id)
+			tt.pressed = append(tt.pressed, TouchPoint{ID: id, X: x, Y: y})
+			t = tt.newTouch(id, x, y, refocusing)
+			tt.touches[id] = t
+		}
+		t.duration = tt.input.TouchPressDuration(id)
+		t.prevX, t.prevY = t.currX, t.currY
+		t.currX, t.currY = tt.input.TouchPosition(id)
+		t.velocities.push(float64(t.currX-t.prevX), float64(t.currY-t.prevY))
+		a := tt.velocitySmoothing
+		t.vx = a*float64(t.currX-t.prevX) + (1-a)*t.vx
+		t.vy = a*float64(t.currY-t.prevY) + (1-a)*t.vy
+		if t.currX == t.prevX && t.currY == t.prevY {
+			t.stillFrames++
+		} else {
+			t.stillFrames = 0
+		}
+
+		if tt.updateForce(id, t) && !tt.paused {
+			if !t.gestures.has(GestureForcePress) {
+				tt.record(GestureForcePress, t.currX, t.currY)
+			}
+			t.gestures.add(GestureForcePress)
+			if !tt.forcePressed {
+				tt.forcePressed = true
+				tt.forcePressX, tt.forcePressY = t.currX, t.currY
+			}
+		}
 	}
+	tt.dropStaleTouches()
+	tt.allReleased = prevCount > 0 && len(tt.touchIDs) == 0
 
 	// Interpret the raw touch data that's been collected into tt.touches into
-	// gestures like two-finger pinch or two-finger pan.
-	if len(tt.touches) == 2 {
-		// Potentially the user is making a pinch gesture with two fingers.
-		// If the diff between their origins is different to the diff between
-		// their currents and if these two are not already a pinch, then this is
-		// a new pinch!
-		id1, id2 := tt.touchIDs[0], tt.touchIDs[1]
-		t1, t2 := tt.touches[id1], tt.touches[id2]
-		originDiff := distance2d(t1.originX, t1.originY, t2.originX, t2.originY)
-		currDiff := distance2d(t1.currX, t1.currY, t2.currX, t2.currY)
-		if tt.pan == nil && math.Abs(originDiff-currDiff) > 10 {
-			if tt.pinch == nil {
-				t1.isPinch = true
-				t2.isPinch = true
-				tt.pinch = &Pinch{
-					ID1:            id1,
-					ID2:            id2,
-					OriginDistance: originDiff,
-					Distance:       currDiff,
-					CenterX:        (t1.currX + t2.currX) / 2,
-					CenterY:        (t1.currY + t2.currY) / 2,
-				}
-			} else {
-				tt.pinch.Distance = currDiff
-			}
+	// gestures like long press, drag, two-finger pinch, two-finger pan or three-finger pan.
+	if !tt.paused {
+		tt.updateTouchDrags()
+		tt.updateLongPress()
+		if tt.longPress != nil && !wasLongPress {
+			tt.started.add(GestureLongPress)
 		}
+		tt.updateDrag()
+	}
+	// A pinch or pan starts with two fingers, but keeps following them if extra fingers
+	// land on the screen, like a palm grazing it. A coasting pan follows its momentum instead.
+	// Fingers are counted by the touches still reported, as one whose release was never reported
+	// stays tracked until it times out.
+	coasting := tt.pan != nil && tt.pan.coasting
+	if !tt.paused && tt.lost == nil && !coasting && (len(tt.touchIDs) == 2 || (len(tt.touchIDs) > 2 && (tt.pinch != nil || tt.pan != nil))) {
+		tt.updateTwoFingerGestures()
+	} else {
+		tt.pinchFrames = 0
+	}
+	if !tt.paused {
+		tt.updateTwoFingerHold()
+	}
+	if !tt.paused && len(tt.touchIDs) == 3 {
+		tt.updateThreeFingerPan()
+	}
+}
 
-		// If the distance between the fingers did not change significantly, this is
-		// potentially a new two-finger horizontal pan. We need to check that one finger
-		// moved horizontally by an arbitraty margin
-		id, id2 := tt.touchIDs[0], tt.touchIDs[1]
-		t, t2 := tt.touches[id], tt.touches[1]
-		diffX := distance(t.originX, t.currX)
-		diffY := distance(t.originY, t.currY)
-		if tt.pinch == nil {
-			if tt.pan == nil && (math.Abs(diffX) > 10 || math.Abs(diffY) > 10) {
-				t.isPan = true
-				t2.isPan = true
-				tt.pan = &TwoFingerPan{
-					ID1:          id,
-					ID2:          id2,
-					OriginX:      t.originX,
-					LastX:        t.currX,
-					OriginY:      t.originY,
-					LastY:        t.currY,
-					isHorizontal: math.Abs(diffX) > 10,
-				}
-			} else if tt.pan != nil {
-				if tt.pan.IsHorizontal() {
-					tt.pan.LastX = t.currX
-				} else {
-					tt.pan.LastY = t.currY
-				}
-			}
+// anchors returns the two fingers two finger gestures follow: the ones of the pinch or pan
+// in progress, or the only two touching the screen otherwise.
+func (tt *TouchTracker) anchors() (ebiten.TouchID, ebiten.TouchID) {
+	switch {
+	case tt.pinch != nil:
+		return tt.pinch.ID1, tt.pinch.ID2
+	case tt.pan != nil && !tt.pan.coasting:
+		return tt.pan.ID1, tt.pan.ID2
+	default:
+		return tt.touchIDs[0], tt.touchIDs[1]
+	}
+}
+
+// updateTwoFingerGestures recognizes pinch and pan gestures while two fingers touch the screen.
+func (tt *TouchTracker) updateTwoFingerGestures() {
+	id1, id2 := tt.anchors()
+	t1, ok1 := tt.touches[id1]
+	t2, ok2 := tt.touches[id2]
+	if !ok1 || !ok2 || t1.consumed || t2.consumed {
+		tt.pinchFrames = 0
+		return
+	}
+	originDiff := distance2d(t1.originX, t1.originY, t2.originX, t2.originY)
+	currDiff := distance2d(t1.currX, t1.currY, t2.currX, t2.currY)
+
+	// Once recognized, a pinch or pan stays committed until one of its fingers is released.
+	if tt.pinch != nil {
+		tt.pinch.PrevDistance = tt.pinch.Distance
+		tt.pinch.Distance = max(currDiff, tt.minPinchDistance)
+		tt.pinch.CenterX, tt.pinch.CenterY = (t1.currX+t2.currX)/2, (t1.currY+t2.currY)/2
+		tt.pinch.Angle = math.Atan2(float64(t2.currY-t1.currY), float64(t2.currX-t1.currX))
+		tt.pinch.x1, tt.pinch.y1, tt.pinch.x2, tt.pinch.y2 = t1.currX, t1.currY, t2.currX, t2.currY
+		return
+	}
+	if tt.pan != nil {
+		tt.pan.PrevX, tt.pan.PrevY = tt.pan.LastX, tt.pan.LastY
+		tt.pan.LastX, tt.pan.LastY = (t1.currX+t2.currX)/2, (t1.currY+t2.currY)/2
+		return
+	}
+
+	// Otherwise classify the movement in a single step. The change in the distance between
+	// the fingers is evidence of a pinch, and the movement of the midpoint between them,
+	// horizontally or vertically, is evidence of a pan. Both are relative to the threshold
+	// of their gesture, and when both pass it the stronger one is recognized, unless
+	// WithGesturePriority says otherwise.
+	diffX := math.Abs(float64(t1.currX+t2.currX-t1.originX-t2.originX)) / 2
+	diffY := math.Abs(float64(t1.currY+t2.currY-t1.originY-t2.originY)) / 2
+	pinchEvidence := math.Abs(originDiff-currDiff) / tt.pinchThreshold
+	activation := tt.panActivationDistance()
+	panEvidence := max(diffX, diffY) / activation
+
+	// Fingers that started too close together can't begin a pinch.
+	canPinch := pinchEvidence > 1 && originDiff >= tt.minPinchDistance && !tt.suppressed(GesturePinch, t1, t2)
+	canPan := panEvidence > 1 && !tt.suppressed(GesturePan, t1, t2)
+	midX, midY := (t1.currX+t2.currX)/2, (t1.currY+t2.currY)/2
+
+	// A pinch must be sustained for a few frames, so a spike in the distance between the fingers
+	// while they are lifted doesn't turn a two finger tap into a pinch.
+	pinchWins := canPinch && (!canPan || tt.outranks(GesturePinch, GesturePan, pinchEvidence, panEvidence))
+	if pinchWins {
+		tt.pinchFrames++
+	} else {
+		tt.pinchFrames = 0
+	}
+	switch {
+	case pinchWins && tt.pinchFrames < tt.pinchMinFrames:
+		tt.ambiguity = ambiguity(pinchEvidence, panEvidence)
+	case pinchWins:
+		tt.pinchFrames = 0
+		t1.gestures.add(GesturePinch)
+		t2.gestures.add(GesturePinch)
+		tt.record(GesturePinch, midX, midY)
+		tt.started.add(GesturePinch)
+		tt.pinch = &Pinch{
+			ID1:            id1,
+			ID2:            id2,
+			OriginDistance: originDiff,
+			PrevDistance:   originDiff,
+			Distance:       max(currDiff, tt.minPinchDistance),
+			CenterX:        midX,
+			CenterY:        midY,
+			OriginCenterX:  (t1.originX + t2.originX) / 2,
+			OriginCenterY:  (t1.originY + t2.originY) / 2,
+			OriginAngle:    math.Atan2(float64(t2.originY-t1.originY), float64(t2.originX-t1.originX)),
+			Angle:          math.Atan2(float64(t2.currY-t1.currY), float64(t2.currX-t1.currX)),
+			x1:             t1.currX,
+			y1:             t1.currY,
+			x2:             t2.currX,
+			y2:             t2.currY,
+			deadzone:       tt.pinchDeadzone,
+
+			rotationThreshold: tt.rotationThreshold,
 		}
+	case canPan:
+		t1.gestures.add(GesturePan)
+		t2.gestures.add(GesturePan)
+		tt.record(GesturePan, midX, midY)
+		tt.started.add(GesturePan)
+		tt.pan = &TwoFingerPan{
+			ID1:          id1,
+			ID2:          id2,
+			OriginX:      (t1.originX + t2.originX) / 2,
+			OriginY:      (t1.originY + t2.originY) / 2,
+			LastX:        midX,
+			LastY:        midY,
+			PrevX:        (t1.prevX + t2.prevX) / 2,
+			PrevY:        (t1.prevY + t2.prevY) / 2,
+			polledX:      (t1.prevX + t2.prevX) / 2,
+			polledY:      (t1.prevY + t2.prevY) / 2,
+			isHorizontal: diffX > activation,
+			threshold:    tt.panThreshold,
+		}
+	default:
+		// Neither gesture was recognized, so score how close they are to each other.
+		tt.ambiguity = ambiguity(pinchEvidence, panEvidence)
+	}
+}
 
+// newTouch returns the touch with the given id pressed at (x, y) in the current frame.
+func (tt *TouchTracker) newTouch(id ebiten.TouchID, x, y int, refocusing bool) *touch {
+	return &touch{
+		typ:     tt.touchType(id),
+		originX: x, originY: y,
+		currX: x, currY: y,
+		pressedAt:  tt.clock,
+		pressFrame: tt.frame,
+		consumed:   refocusing || (tt.paused && tt.pauseInput == PauseDrop) || tt.isPalmPress(x, y),
+		afterTap:   tt.afterTap(x, y),
 	}
 }
 
-// IsTouchingThree returns if the screen is being touched with three fingers.
+// addTap records tap as made in the current frame, and appends it to the tap history,
+// dropping the oldest one if full.
+func (tt *TouchTracker) addTap(tap tapEvent) {
+	tap.frame = tt.frame
+	tt.taps = append(tt.taps, tap)
+	tt.record(GestureTap, tap.X, tap.Y)
+	if len(tt.tapHistory) == maxTapHistory {
+		copy(tt.tapHistory, tt.tapHistory[1:])
+		tt.tapHistory = tt.tapHistory[:maxTapHistory-1]
+	}
+	tt.tapHistory = append(tt.tapHistory, tap)
+}
+
+// ClearTaps drops the taps of the last update frame, the tap history, any tap queued while paused,
+// any tap waiting to become a double tap and any tap waiting for the other fingers of a multi
+// finger tap, without disrupting the touches being tracked or an ongoing pinch or pan.
+//
+// Useful when transitioning between UI states, so taps from the previous state don't carry over.
 //
 // This function is concurrent safe.
-func (tt *TouchTracker) IsTouchingThree() bool {
+func (tt *TouchTracker) ClearTaps() {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	tt.taps = tt.taps[:0]
+	tt.tapHistory = tt.tapHistory[:0]
+	tt.queuedTap = nil
+	tt.tapChain = nil
+	tt.doubleTap = nil
+	tt.tripleTap = nil
+	tt.singleTap = nil
+	tt.pendingTaps = tt.pendingTaps[:0]
+	tt.pendingSince = 0
+	tt.soloTaps = nil
+}
+
+// TouchCount returns how many fingers are touching the screen.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TouchCount() int {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
-	return len(tt.touchIDs) == 3
+	return len(tt.touchIDs)
+}
+
+// IsTouchingN returns if the screen is being touched with exactly n fingers.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) IsTouchingN(n int) bool {
+	return tt.TouchCount() == n
+}
+
+// IsTouchingThree returns if the screen is being touched with three fingers.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) IsTouchingThree() bool {
+	return tt.IsTouchingN(3)
 }
 
 // IsTouchingTwo returns if the screen is being touched with two fingers.
 //
 // This function is concurrent safe.
 func (tt *TouchTracker) IsTouchingTwo() bool {
-	tt.m.RLock()
-	defer tt.m.RUnlock()
-	return len(tt.touchIDs) == 2
+	return tt.IsTouchingN(2)
 }
 
 // IsTouching returns if the screen is being touched at all.
 //
 // This function is concurrent safe.
 func (tt *TouchTracker) IsTouching() bool {
-	tt.m.RLock()
-	defer tt.m.RUnlock()
-	return len(tt.touchIDs) > 0
+	return tt.TouchCount() > 0
 }
 
 // TappedThree returns Tap coordinates if a three finger tap was made (released) in the last update frame.
+// The fingers may be lifted a few frames apart, as set by WithMultiTapWindow.
 //
 // This function is concurrent safe.
 func (tt *TouchTracker) TappedThree() (Tap, Tap, Tap, bool) {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
-	if len(tt.taps) == 3 {
-		return tt.taps[0], tt.taps[1], tt.taps[2], true
+	if len(tt.taps) == 3 && !tt.handled.has(GestureTap) {
+		return tt.worldTap(tt.taps[0].Tap), tt.worldTap(tt.taps[1].Tap), tt.worldTap(tt.taps[2].Tap), true
 	}
 	return Tap{}, Tap{}, Tap{}, false
 }
 
 // TappedTwo returns Tap coordinates if a two finger tap was made (released) in the last update frame.
+// The fingers may be lifted a few frames apart, as set by WithMultiTapWindow.
 //
 // This function is concurrent safe.
 func (tt *TouchTracker) TappedTwo() (Tap, Tap, bool) {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
-	if len(tt.taps) == 2 {
-		return tt.taps[0], tt.taps[1], true
+	if len(tt.taps) == 2 && !tt.handled.has(GestureTap) {
+		return tt.worldTap(tt.taps[0].Tap), tt.worldTap(tt.taps[1].Tap), true
+	}
+	return Tap{}, Tap{}, false
+}
+
+// TappedTwoCenter returns the midpoint of the taps if a two finger tap was made (released)
+// in the last update frame.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TappedTwoCenter() (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if len(tt.taps) == 2 && !tt.handled.has(GestureTap) {
+		return tt.worldTap(tt.tapCenter()), true
+	}
+	return Tap{}, false
+}
+
+// TappedThreeCenter returns the centroid of the taps if a three finger tap was made (released)
+// in the last update frame.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TappedThreeCenter() (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if len(tt.taps) == 3 && !tt.handled.has(GestureTap) {
+		return tt.worldTap(tt.tapCenter()), true
+	}
+	return Tap{}, false
+}
+
+// tapCenter returns the centroid of the taps of the last update frame, with the longest
+// duration and movement of them.
+func (tt *TouchTracker) tapCenter() Tap {
+	var c Tap
+	for _, tap := range tt.taps {
+		c.X += tap.X
+		c.Y += tap.Y
+		c.Duration = max(c.Duration, tap.Duration)
+		c.Movement = max(c.Movement, tap.Movement)
+	}
+	c.X /= len(tt.taps)
+	c.Y /= len(tt.taps)
+	return c
+}
+
+// TappedTwoApart returns Tap coordinates if a two finger tap was made (released) in the last update frame
+// with the fingers separated, either horizontally or vertically, by at least the thumb tap separation.
+//
+// This tells a deliberate tap with both thumbs apart from two fingers tapping close together.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TappedTwoApart() (Tap, Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if len(tt.taps) == 2 && !tt.handled.has(GestureTap) {
+		a, b := tt.taps[0].Tap, tt.taps[1].Tap
+		if distance(a.X, b.X) >= float64(tt.thumbTapSeparation) || distance(a.Y, b.Y) >= float64(tt.thumbTapSeparation) {
+			return tt.worldTap(a), tt.worldTap(b), true
+		}
 	}
 	return Tap{}, Tap{}, false
 }
 
+// TapsByID returns the Tap coordinates of every finger released as a tap in the last update frame,
+// keyed by touch ID, to handle fingers independently, like one per player in a local multiplayer game.
+// The returned map is owned by the caller.
+//
+// It includes the taps of multi finger taps, but not the second and third taps of double and triple
+// taps, which are reported by DoubleTapped and TripleTapped. Fingers moving at the same time can still
+// be recognized as a pinch or pan, which keeps them from being taps unless WithSuppressionRules says otherwise.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TapsByID() map[ebiten.TouchID]Tap {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	taps := make(map[ebiten.TouchID]Tap, len(tt.taps))
+	if tt.handled.has(GestureTap) {
+		return taps
+	}
+	for _, tap := range tt.taps {
+		taps[tap.id] = tt.worldTap(tap.Tap)
+	}
+	return taps
+}
+
+// Taps returns every tap made (released) in the last update frame, however many fingers made them,
+// to handle taps with four or more fingers that TappedOne, TappedTwo and TappedThree don't match.
+// The returned slice is owned by the caller.
+//
+// Like TapsByID, it includes the taps of multi finger taps, but not the second and third taps of
+// double and triple taps.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Taps() []Tap {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.handled.has(GestureTap) || len(tt.taps) == 0 {
+		return nil
+	}
+	taps := make([]Tap, len(tt.taps))
+	for i, tap := range tt.taps {
+		taps[i] = tt.worldTap(tap.Tap)
+	}
+	return taps
+}
+
 // TappedOne returns Tap coordinates if a tap was made (released) in the last update frame.
 //
 // This function is concurrent safe.
 func (tt *TouchTracker) TappedOne() (Tap, bool) {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
-	if len(tt.taps) == 1 {
-		return tt.taps[0], true
+	if len(tt.taps) == 1 && !tt.handled.has(GestureTap) {
+		return tt.worldTap(tt.taps[0].Tap), true
 	}
 	return Tap{}, false
 }
@@ -279,12 +888,68 @@ func (tt *TouchTracker) TappedOne() (Tap, bool) {
 func (tt *TouchTracker) TwoFingerPan() (TwoFingerPan, bool) {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
-	if tt.pan != nil {
-		return *tt.pan, true
+	if tt.pan != nil && !tt.handled.has(GesturePan) {
+		return tt.worldPan(*tt.pan), true
+	}
+	return TwoFingerPan{}, false
+}
+
+// PanJustStarted returns the TwoFingerPan data if a two finger pan was recognized in the last update frame,
+// the first frame TwoFingerPan reports it.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) PanJustStarted() (TwoFingerPan, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.pan != nil && tt.started.has(GesturePan) && !tt.handled.has(GesturePan) {
+		return tt.worldPan(*tt.pan), true
 	}
 	return TwoFingerPan{}, false
 }
 
+// PanJustEnded returns the final TwoFingerPan data if a two finger pan ended in the last update frame,
+// because one of its fingers was released or, with WithMomentum, it stopped coasting.
+//
+// Pans canceled by CancelGesture, Pause or Reset are not reported.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) PanJustEnded() (TwoFingerPan, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.panEnded != nil && !tt.handled.has(GesturePan) {
+		return tt.worldPan(*tt.panEnded), true
+	}
+	return TwoFingerPan{}, false
+}
+
+// PinchJustStarted returns the Pinch data if a pinch was recognized in the last update frame,
+// the first frame Pinch reports it.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) PinchJustStarted() (Pinch, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.pinch != nil && tt.started.has(GesturePinch) && !tt.handled.has(GesturePinch) {
+		return tt.worldPinch(*tt.pinch), true
+	}
+	return Pinch{}, false
+}
+
+// PinchJustEnded returns the final Pinch data if a pinch ended in the last update frame,
+// because one of its fingers was released.
+//
+// Pinches canceled by CancelGesture, Pause or Reset are not reported.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) PinchJustEnded() (Pinch, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if tt.pinchEnded != nil && !tt.handled.has(GesturePinch) {
+		return tt.worldPinch(*tt.pinchEnded), true
+	}
+	return Pinch{}, false
+}
+
 // Pinch returns the latest Pinch data if a pinch gesture is being made.
 //
 // Pinch data updates every Update frame.
@@ -293,21 +958,220 @@ func (tt *TouchTracker) TwoFingerPan() (TwoFingerPan, bool) {
 func (tt *TouchTracker) Pinch() (Pinch, bool) {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
-	if tt.pinch != nil {
-		return *tt.pinch, true
+	if tt.pinch != nil && !tt.handled.has(GesturePinch) {
+		return tt.worldPinch(*tt.pinch), true
 	}
 	return Pinch{}, false
 }
 
-// GetFirstTouchPosition return X, Y coordinates of the first touch recorded, if any.
+// AllTouchesReleased returns if the last active touch was released in the last update frame,
+// signaling the user finished interacting with the screen.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) AllTouchesReleased() bool {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return tt.allReleased
+}
+
+// TapAfterPinch returns Tap coordinates if a one finger tap was made (released) in the last update frame,
+// within windowFrames after a pinch concluded.
+//
+// The fingers that made the pinch never count as taps when lifted, so only a new,
+// intentional tap is reported.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TapAfterPinch(windowFrames int) (Tap, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	if len(tt.taps) == 1 && !tt.handled.has(GestureTap) && tt.pinchEndFrame > 0 && tt.frame-tt.pinchEndFrame <= windowFrames {
+		return tt.worldTap(tt.taps[0].Tap), true
+	}
+	return Tap{}, false
+}
+
+// GetFirstTouchPosition return X, Y coordinates of the first touch recorded (the one with the lowest ID), if any.
 //
 // This function is concurrent safe.
 func (tt *TouchTracker) GetFirstTouchPosition() (int, int, bool) {
 	tt.m.RLock()
 	defer tt.m.RUnlock()
 	if len(tt.touchIDs) > 0 {
-		x, y := ebiten.TouchPosition(tt.touchIDs[0])
+		x, y := tt.flip(tt.input.TouchPosition(tt.touchIDs[0]))
 		return x, y, true
 	}
 	return -1, -1, false
 }
+
+// TouchPositions returns the position of every active touch as of the last update frame,
+// ordered by ID. The returned slice is owned by the caller.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TouchPositions() []TouchPoint {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	points := make([]TouchPoint, 0, len(tt.touchIDs))
+	for _, id := range tt.touchIDs {
+		if t, ok := tt.touches[id]; ok {
+			x, y := tt.flip(t.currX, t.currY)
+			points = append(points, TouchPoint{ID: id, X: x, Y: y})
+		}
+	}
+	return points
+}
+
+// TouchInfo is a snapshot of an active touch, independent of the gestures it takes part in.
+type TouchInfo struct {
+	OriginX, OriginY int
+	CurrX, CurrY     int
+
+	// Duration is the amount of frames the touch has been pressed.
+	Duration int
+
+	// VelocityX, VelocityY is the smoothed speed of the touch, in pixels per frame,
+	// as set by WithVelocitySmoothing.
+	VelocityX, VelocityY float64
+
+	// Type is what made the touch, as reported by WithTouchTypeProvider.
+	Type TouchType
+}
+
+// Touch returns the TouchInfo of the active touch with the given id, as of the last update frame.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) Touch(id ebiten.TouchID) (TouchInfo, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	t, ok := tt.touches[id]
+	if !ok {
+		return TouchInfo{}, false
+	}
+	return tt.touchInfo(t), true
+}
+
+// ForEachTouch calls fn with the TouchInfo of every active touch as of the last update frame,
+// ordered by ID, until fn returns false. Unlike TouchPositions,
+// it doesn't allocate.
+//
+// fn is called while the tracker is locked for reading, so it must not call back into the tracker,
+// or it may deadlock with a concurrent Update.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ForEachTouch(fn func(id ebiten.TouchID, info TouchInfo) bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	for _, id := range tt.touchIDs {
+		if t, ok := tt.touches[id]; ok && !fn(id, tt.touchInfo(t)) {
+			return
+		}
+	}
+}
+
+func (tt *TouchTracker) touchInfo(t *touch) TouchInfo {
+	info := TouchInfo{
+		Duration:  t.duration,
+		VelocityX: t.vx,
+		VelocityY: t.vy,
+		Type:      t.typ,
+	}
+	info.OriginX, info.OriginY = tt.flip(t.originX, t.originY)
+	info.CurrX, info.CurrY = tt.flip(t.currX, t.currY)
+	return info
+}
+
+// ActiveTouchIDs returns the IDs of the active touches as of the last update frame, in ascending
+// order. The returned slice is owned by the caller.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) ActiveTouchIDs() []ebiten.TouchID {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	return append([]ebiten.TouchID(nil), tt.touchIDs...)
+}
+
+// TouchBounds returns the smallest rectangle containing every active touch as of the last update frame,
+// or false if there are no active touches. The rectangle includes the touches on its bottom-right edges.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TouchBounds() (image.Rectangle, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	var r image.Rectangle
+	n := 0
+	for _, id := range tt.touchIDs {
+		t, ok := tt.touches[id]
+		if !ok {
+			continue
+		}
+		x, y := tt.flip(t.currX, t.currY)
+		p := image.Rect(x, y, x+1, y+1)
+		if n == 0 {
+			r = p
+		} else {
+			r = r.Union(p)
+		}
+		n++
+	}
+	return r, n > 0
+}
+
+// IsTouchingRect returns if any active touch is inside rect as of the last update frame.
+//
+// Positions are compared like those reported by TouchPositions.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) IsTouchingRect(rect image.Rectangle) bool {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	for _, id := range tt.touchIDs {
+		if t, ok := tt.touches[id]; ok && tt.inRect(t, rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// TouchesInRect returns the IDs of the active touches inside rect as of the last update frame,
+// ordered by ID. The returned slice is owned by the caller.
+//
+// Positions are compared like those reported by TouchPositions.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TouchesInRect(rect image.Rectangle) []ebiten.TouchID {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	var ids []ebiten.TouchID
+	for _, id := range tt.touchIDs {
+		if t, ok := tt.touches[id]; ok && tt.inRect(t, rect) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// inRect returns if touch t is inside rect.
+func (tt *TouchTracker) inRect(t *touch, rect image.Rectangle) bool {
+	return image.Pt(tt.flip(t.currX, t.currY)).In(rect)
+}
+
+// TouchCentroid returns the average position of every active touch as of the last update frame,
+// or false if there are no active touches.
+//
+// This function is concurrent safe.
+func (tt *TouchTracker) TouchCentroid() (int, int, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+	var x, y, n int
+	for _, id := range tt.touchIDs {
+		if t, ok := tt.touches[id]; ok {
+			x += t.currX
+			y += t.currY
+			n++
+		}
+	}
+	if n == 0 {
+		return -1, -1, false
+	}
+	x, y = tt.flip(x/n, y/n)
+	return x, y, true
+}
@@ -0,0 +1,833 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import (
+	"encoding/json"
+	"image"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// scriptedInput is an InputSource that plays touch sequences scripted frame by frame.
+type scriptedInput struct {
+	down     map[ebiten.TouchID]*scriptedTouch
+	pressed  []ebiten.TouchID
+	released []ebiten.TouchID
+
+	// reversed makes AppendTouchIDs report the active touches in descending ID order.
+	reversed bool
+}
+
+type scriptedTouch struct {
+	x, y     int
+	duration int
+}
+
+// newScripted returns a tracker configured by opts reading touches from a scriptedInput.
+func newScripted(opts ...Option) (*TouchTracker, *scriptedInput) {
+	in := &scriptedInput{down: make(map[ebiten.TouchID]*scriptedTouch)}
+	return NewTouchTracker(append(opts, WithInputSource(in))...), in
+}
+
+func (in *scriptedInput) press(id ebiten.TouchID, x, y int) {
+	in.down[id] = &scriptedTouch{x: x, y: y}
+	in.pressed = append(in.pressed, id)
+}
+
+func (in *scriptedInput) move(id ebiten.TouchID, x, y int) {
+	in.down[id].x, in.down[id].y = x, y
+}
+
+func (in *scriptedInput) release(id ebiten.TouchID) {
+	delete(in.down, id)
+	in.released = append(in.released, id)
+}
+
+// lose stops reporting the touch with the given id without reporting its release,
+// like some platforms do with fingers sliding off the screen.
+func (in *scriptedInput) lose(id ebiten.TouchID) {
+	delete(in.down, id)
+}
+
+// step runs an update frame of tt, a tracker or router, with the touches scripted since
+// the previous one.
+func (in *scriptedInput) step(tt interface{ Update() }) {
+	for _, t := range in.down {
+		t.duration++
+	}
+	tt.Update()
+	in.pressed = in.pressed[:0]
+	in.released = in.released[:0]
+}
+
+// steps runs n update frames of tt.
+func (in *scriptedInput) steps(tt interface{ Update() }, n int) {
+	for range n {
+		in.step(tt)
+	}
+}
+
+func (in *scriptedInput) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	start := len(touches)
+	for id := range in.down {
+		touches = append(touches, id)
+	}
+	slices.Sort(touches[start:])
+	if in.reversed {
+		slices.Reverse(touches[start:])
+	}
+	return touches
+}
+
+func (in *scriptedInput) AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return append(touches, in.pressed...)
+}
+
+func (in *scriptedInput) IsTouchJustReleased(id ebiten.TouchID) bool {
+	return slices.Contains(in.released, id)
+}
+
+func (in *scriptedInput) TouchPosition(id ebiten.TouchID) (int, int) {
+	if t, ok := in.down[id]; ok {
+		return t.x, t.y
+	}
+	return 0, 0
+}
+
+func (in *scriptedInput) TouchPressDuration(id ebiten.TouchID) int {
+	if t, ok := in.down[id]; ok {
+		return t.duration
+	}
+	return 0
+}
+
+func TestPanWithTouchIDsOtherThanZeroAndOne(t *testing.T) {
+	tt, in := newScripted()
+	in.press(7, 100, 100)
+	in.press(12, 200, 100)
+	in.step(tt)
+	for i := 1; i <= 4; i++ {
+		in.move(7, 100, 100+10*i)
+		in.move(12, 200, 100+10*i)
+		in.step(tt)
+	}
+
+	pan, ok := tt.TwoFingerPan()
+	if !ok {
+		t.Fatal("expected a two finger pan")
+	}
+	if pan.ID1 != 7 || pan.ID2 != 12 {
+		t.Errorf("pan fingers = %d, %d, want 7, 12", pan.ID1, pan.ID2)
+	}
+	if pan.OriginX != 150 || pan.OriginY != 100 || pan.LastX != 150 || pan.LastY != 140 {
+		t.Errorf("pan from (%d, %d) to (%d, %d), want from (150, 100) to (150, 140)",
+			pan.OriginX, pan.OriginY, pan.LastX, pan.LastY)
+	}
+	if !pan.IsVertical() {
+		t.Error("expected a vertical pan")
+	}
+}
+
+func TestCoastingPanWithExtraFinger(t *testing.T) {
+	tt, in := newScripted(WithMomentum(0.1))
+	in.press(1, 100, 100)
+	in.press(2, 200, 100)
+	in.step(tt)
+	for i := 1; i <= 4; i++ {
+		in.move(1, 100+20*i, 100)
+		in.move(2, 200+20*i, 100)
+		in.step(tt)
+	}
+	if _, ok := tt.TwoFingerPan(); !ok {
+		t.Fatal("expected a two finger pan")
+	}
+
+	// A third finger lands and one of the pan fingers is lifted while moving,
+	// leaving two fingers down while the pan coasts.
+	in.press(3, 400, 400)
+	in.move(1, 200, 100)
+	in.move(2, 300, 100)
+	in.step(tt)
+	in.release(1)
+	in.step(tt)
+	if pan, ok := tt.TwoFingerPan(); !ok || !pan.IsCoasting() {
+		t.Fatal("expected the pan to coast")
+	}
+	in.steps(tt, 3)
+	if pan, ok := tt.TwoFingerPan(); ok && !pan.IsCoasting() {
+		t.Error("expected the pan to keep coasting or end")
+	}
+}
+
+func TestTouchReleaseNeverReported(t *testing.T) {
+	tt, in := newScripted()
+	in.press(1, 100, 100)
+	in.step(tt)
+	in.lose(1)
+	in.step(tt)
+
+	in.press(2, 300, 300)
+	in.step(tt)
+	in.press(3, 400, 300)
+	in.step(tt)
+	in.press(4, 500, 300)
+	in.step(tt)
+
+	ids := tt.ActiveTouchIDs()
+	if !slices.Equal(ids, []ebiten.TouchID{2, 3, 4}) {
+		t.Errorf("active touches = %v, want [2 3 4]", ids)
+	}
+}
+
+func TestTapNeedsShortAndStillPress(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames int
+		moveX  int
+		tap    bool
+	}{
+		{"short and still", 5, 0, true},
+		{"short and moved", 5, 20, false},
+		{"long and still", 40, 0, false},
+		{"long and moved", 40, 20, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt, in := newScripted()
+			in.press(1, 100, 100)
+			in.step(tt)
+			in.steps(tt, test.frames-1)
+			in.move(1, 100+test.moveX, 100)
+			in.step(tt)
+			in.release(1)
+			in.step(tt)
+
+			if _, ok := tt.TappedOne(); ok != test.tap {
+				t.Errorf("tapped = %v, want %v", ok, test.tap)
+			}
+		})
+	}
+}
+
+func TestBriskTwoFingerTapWithIncidentalSpread(t *testing.T) {
+	// The pinch threshold is lowered below the 4px the fingers spread while lifted,
+	// so only the pinch min frames keep the tap from becoming a pinch.
+	tt, in := newScripted(WithPinchThreshold(3), WithTapMaxMovement(5))
+	in.press(1, 100, 100)
+	in.press(2, 200, 100)
+	in.step(tt)
+	in.move(1, 98, 100)
+	in.move(2, 202, 100)
+	in.step(tt)
+	in.release(1)
+	in.release(2)
+	in.step(tt)
+
+	if _, _, ok := tt.TappedTwo(); !ok {
+		t.Error("expected a two finger tap")
+	}
+	if _, ok := tt.PinchJustEnded(); ok {
+		t.Error("expected no pinch")
+	}
+}
+
+// moveTwoFingers presses touches 1 and 2 at (100, 100) and (200, 100), and moves them by
+// (dx1, dy1) and (dx2, dy2) on each of the given amount of frames.
+func moveTwoFingers(tt *TouchTracker, in *scriptedInput, frames, dx1, dy1, dx2, dy2 int) {
+	in.press(1, 100, 100)
+	in.press(2, 200, 100)
+	in.step(tt)
+	for i := 1; i <= frames; i++ {
+		in.move(1, 100+dx1*i, 100+dy1*i)
+		in.move(2, 200+dx2*i, 100+dy2*i)
+		in.step(tt)
+	}
+}
+
+func TestTwoFingerClassification(t *testing.T) {
+	tests := []struct {
+		name               string
+		dx1, dy1, dx2, dy2 int
+		pinch, pan         bool
+	}{
+		{"pure pinch", -8, 0, 8, 0, true, false},
+		{"pure pan", 0, 8, 0, 8, false, true},
+
+		// The fingers spread by as much as their midpoint moves, so both gestures pass their
+		// threshold by the same margin, and the pinch wins the tie.
+		{"ambiguous diagonal", 0, 8, 8, 8, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt, in := newScripted()
+			moveTwoFingers(tt, in, 4, test.dx1, test.dy1, test.dx2, test.dy2)
+			_, pinch := tt.Pinch()
+			_, pan := tt.TwoFingerPan()
+			if pinch != test.pinch || pan != test.pan {
+				t.Fatalf("pinch = %v, pan = %v, want %v, %v", pinch, pan, test.pinch, test.pan)
+			}
+
+			// The gesture stays committed whatever the fingers do next.
+			for i := 1; i <= 4; i++ {
+				in.move(1, 100+40*i, 300)
+				in.move(2, 110+40*i, 300)
+				in.step(tt)
+			}
+			_, pinch = tt.Pinch()
+			_, pan = tt.TwoFingerPan()
+			if pinch != test.pinch || pan != test.pan {
+				t.Errorf("after moving on, pinch = %v, pan = %v, want %v, %v", pinch, pan, test.pinch, test.pan)
+			}
+		})
+	}
+}
+
+func TestPinchFollowsFingersWhenIDsAreReordered(t *testing.T) {
+	tt, in := newScripted()
+	in.press(5, 100, 100)
+	in.press(3, 200, 100)
+	in.step(tt)
+	for i := 1; i <= 6; i++ {
+		// The source reports the active touches in a different order on every frame.
+		in.reversed = i%2 == 0
+		in.move(5, 100-10*i, 100)
+		in.move(3, 200+10*i, 100)
+		in.step(tt)
+	}
+
+	p, ok := tt.Pinch()
+	if !ok {
+		t.Fatal("expected a pinch")
+	}
+	if p.ID1 != 3 || p.ID2 != 5 {
+		t.Errorf("pinch fingers = %d, %d, want 3, 5", p.ID1, p.ID2)
+	}
+	if x, _ := p.Finger1(); x != 260 {
+		t.Errorf("first finger at x = %d, want 260", x)
+	}
+	if x, _ := p.Finger2(); x != 40 {
+		t.Errorf("second finger at x = %d, want 40", x)
+	}
+	if ids := tt.ActiveTouchIDs(); !slices.Equal(ids, []ebiten.TouchID{3, 5}) {
+		t.Errorf("active touches = %v, want [3 5]", ids)
+	}
+}
+
+func TestPressWhileAnotherFingerIsHeld(t *testing.T) {
+	tt, in := newScripted()
+	in.press(1, 10, 10)
+	in.steps(tt, 2)
+	in.press(2, 300, 300)
+	in.step(tt)
+
+	if ids := tt.ActiveTouchIDs(); !slices.Equal(ids, []ebiten.TouchID{1, 2}) {
+		t.Fatalf("active touches = %v, want [1 2]", ids)
+	}
+	held, _ := tt.Touch(1)
+	if held.CurrX != 10 || held.CurrY != 10 || held.Duration != 3 {
+		t.Errorf("held touch at (%d, %d) for %d frames, want (10, 10) for 3", held.CurrX, held.CurrY, held.Duration)
+	}
+	pressed, _ := tt.Touch(2)
+	if pressed.CurrX != 300 || pressed.CurrY != 300 || pressed.Duration != 1 {
+		t.Errorf("new touch at (%d, %d) for %d frames, want (300, 300) for 1", pressed.CurrX, pressed.CurrY, pressed.Duration)
+	}
+	if frame := tt.FrameInput(); len(frame.Pressed) != 1 || frame.Pressed[0].ID != 2 {
+		t.Errorf("pressed = %v, want only touch 2", frame.Pressed)
+	}
+}
+
+func TestReusedTouchIDStartsFresh(t *testing.T) {
+	tests := []struct {
+		name      string
+		sameFrame bool
+	}{
+		{"next frame", false},
+		{"same frame", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt, in := newScripted()
+			in.press(0, 100, 100)
+			in.step(tt)
+			for i := 1; i <= 3; i++ {
+				in.move(0, 100+20*i, 100)
+				in.step(tt)
+			}
+			if _, ok := tt.Drag(); !ok {
+				t.Fatal("expected a drag")
+			}
+
+			in.release(0)
+			if !test.sameFrame {
+				in.step(tt)
+			}
+			in.press(0, 400, 400)
+			in.step(tt)
+
+			info, ok := tt.Touch(0)
+			if !ok || info.OriginX != 400 || info.OriginY != 400 || info.Duration != 1 {
+				t.Errorf("touch 0 = %+v, want a new touch at (400, 400)", info)
+			}
+			if _, ok := tt.Drag(); ok {
+				t.Error("expected the drag not to carry over to the new touch")
+			}
+			if class, _ := tt.TouchClassification(0); class != ClassTap {
+				t.Errorf("new touch classified as %v, want ClassTap", class)
+			}
+		})
+	}
+}
+
+func TestPinchStartingTooClose(t *testing.T) {
+	tests := []struct {
+		name  string
+		minPx float64
+		pinch bool
+	}{
+		{"without minimum", 0, true},
+		{"below minimum", 20, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt, in := newScripted(WithMinPinchDistance(test.minPx))
+			in.press(1, 100, 100)
+			in.press(2, 104, 100)
+			in.step(tt)
+			for i := 1; i <= 4; i++ {
+				in.move(1, 100-10*i, 100)
+				in.move(2, 104+10*i, 100)
+				in.step(tt)
+			}
+
+			p, ok := tt.Pinch()
+			if ok != test.pinch {
+				t.Fatalf("pinch = %v, want %v", ok, test.pinch)
+			}
+			if ok && p.OriginDistance != 4 {
+				t.Errorf("origin distance = %v, want 4", p.OriginDistance)
+			}
+		})
+	}
+}
+
+func TestClearTapsDropsTapsWaitingForCompanions(t *testing.T) {
+	tt, in := newScripted()
+	in.press(1, 100, 100)
+	in.press(2, 200, 100)
+	in.step(tt)
+	in.release(1)
+	in.step(tt)
+
+	tt.ClearTaps()
+	for range 60 {
+		in.step(tt)
+		if _, ok := tt.TappedOne(); ok {
+			t.Fatal("tap released before ClearTaps was reported")
+		}
+	}
+}
+
+func TestDisabledGestureCallbacks(t *testing.T) {
+	tt, in := newScripted()
+	pinches, pans := 0, 0
+	tt.OnPinch(func(Pinch) { pinches++ })
+	tt.OnPan(func(TwoFingerPan) { pans++ })
+
+	moveTwoFingers(tt, in, 5, -10, 0, 10, 0)
+	if pinches == 0 {
+		t.Fatal("OnPinch wasn't called while pinching")
+	}
+	tt.EnableGesture(GesturePinch, false)
+	pinches = 0
+	for i := 1; i <= 5; i++ {
+		in.move(1, 50-10*i, 100)
+		in.move(2, 250+10*i, 100)
+		in.step(tt)
+	}
+	if pinches != 0 {
+		t.Errorf("OnPinch called %d times after disabling pinch", pinches)
+	}
+	tt.EnableGesture(GesturePinch, true)
+	in.move(2, 310, 100)
+	in.step(tt)
+	if pinches != 1 {
+		t.Errorf("OnPinch called %d times after enabling pinch again, want 1", pinches)
+	}
+
+	in.release(1)
+	in.release(2)
+	in.step(tt)
+	moveTwoFingers(tt, in, 5, 0, 10, 0, 10)
+	if pans == 0 {
+		t.Fatal("OnPan wasn't called while panning")
+	}
+	tt.EnableGesture(GesturePan, false)
+	pans = 0
+	in.move(1, 100, 160)
+	in.move(2, 200, 160)
+	in.step(tt)
+	if pans != 0 {
+		t.Errorf("OnPan called %d times after disabling pan", pans)
+	}
+}
+
+func TestFlingDirectionFollowsLatestMovement(t *testing.T) {
+	tt, in := newScripted()
+	in.press(1, 100, 100)
+	in.step(tt)
+	x, y := 100, 100
+	for range 6 {
+		x += 30
+		in.move(1, x, y)
+		in.step(tt)
+	}
+	for range 6 {
+		y -= 25
+		in.move(1, x, y)
+		in.step(tt)
+	}
+	in.release(1)
+	in.step(tt)
+
+	f, ok := tt.Fling()
+	if !ok {
+		t.Fatal("fling not recognized")
+	}
+	if f.Direction != DirUp {
+		t.Errorf("direction = %v, want DirUp", f.Direction)
+	}
+}
+
+func TestFingersLiftedTogetherAreNotFlings(t *testing.T) {
+	// Map iteration order varies, so try enough times for either finger to be released first.
+	for range 20 {
+		tt, in := newScripted()
+		// Without pinch and pan the fingers aren't consumed by a two finger gesture.
+		tt.EnableGesture(GesturePinch, false)
+		tt.EnableGesture(GesturePan, false)
+		in.press(1, 100, 100)
+		in.press(2, 300, 100)
+		in.step(tt)
+		for i := 1; i <= 5; i++ {
+			in.move(1, 100+30*i, 100)
+			in.move(2, 300+30*i, 100)
+			in.step(tt)
+		}
+		in.release(1)
+		in.release(2)
+		in.step(tt)
+
+		if _, ok := tt.Fling(); ok {
+			t.Fatal("fingers lifted together recognized as a fling")
+		}
+	}
+}
+
+func TestRouterHandsTouchesOver(t *testing.T) {
+	for _, claim := range []bool{false, true} {
+		in := &scriptedInput{down: make(map[ebiten.TouchID]*scriptedTouch)}
+		r := NewTouchRouter(WithInputSource(in))
+		list := r.Layer(0, nil)
+		button := r.Layer(1, func(x, y int) bool { return x < 150 })
+
+		in.press(3, 100, 100)
+		in.step(r)
+		if button.TouchCount() != 1 || list.TouchCount() != 0 {
+			t.Fatalf("claim %v: touch not owned by the topmost layer hit", claim)
+		}
+
+		if claim {
+			r.ClaimTouch(list, 3)
+		} else {
+			r.PassTouch(button, 3)
+		}
+		in.step(r)
+		if _, ok := button.TappedOne(); ok {
+			t.Errorf("claim %v: touch handed over tapped the layer it left", claim)
+		}
+		if button.TouchCount() != 0 || list.TouchCount() != 1 {
+			t.Fatalf("claim %v: touch not handed to the layer beneath", claim)
+		}
+
+		in.move(3, 100, 160)
+		in.steps(r, 3)
+		if _, ok := list.Drag(); !ok {
+			t.Errorf("claim %v: layer taking the touch doesn't follow it", claim)
+		}
+	}
+}
+
+func TestZeroCameraZoom(t *testing.T) {
+	var cam Camera
+	if x, y := cam.ScreenToWorld(30, 40); x != 30 || y != 40 {
+		t.Errorf("ScreenToWorld(30, 40) = (%v, %v), want (30, 40)", x, y)
+	}
+	if x, y := cam.WorldToScreen(30, 40); x != 30 || y != 40 {
+		t.Errorf("WorldToScreen(30, 40) = (%v, %v), want (30, 40)", x, y)
+	}
+
+	cam = ApplyPinchZoom(cam, Pinch{CenterX: 100, CenterY: 100, Distance: 200, PrevDistance: 100})
+	if cam.Zoom != 2 {
+		t.Errorf("zoom after doubling the pinch distance = %v, want 2", cam.Zoom)
+	}
+	if x, y := cam.WorldToScreen(100, 100); x != 100 || y != 100 {
+		t.Errorf("pinch center drawn at (%v, %v), want (100, 100)", x, y)
+	}
+}
+
+func TestSnapshotRoundTripOfCoastingPan(t *testing.T) {
+	tt, in := newScripted(WithMomentum(0.1))
+	moveTwoFingers(tt, in, 4, 20, 0, 20, 0)
+	in.release(1)
+	in.release(2)
+	in.step(tt)
+	if pan, ok := tt.TwoFingerPan(); !ok || !pan.IsCoasting() {
+		t.Fatal("expected the pan to coast")
+	}
+
+	data, err := json.Marshal(tt.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s TrackerState
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	restored, in := newScripted(WithMomentum(0.1))
+	restored.LoadSnapshot(s)
+	if pan, ok := restored.TwoFingerPan(); !ok || !pan.IsCoasting() {
+		t.Fatal("restored pan isn't coasting")
+	}
+
+	in.steps(restored, 100)
+	if _, ok := restored.TwoFingerPan(); ok {
+		t.Fatal("restored coasting pan never ended")
+	}
+	moveTwoFingers(restored, in, 4, 0, 20, 0, 20)
+	if _, ok := restored.TwoFingerPan(); !ok {
+		t.Error("new pan not recognized after the restored one ended")
+	}
+}
+
+func TestLoadSnapshotDropsGesturesOfMissingTouches(t *testing.T) {
+	tt, in := newScripted()
+	tt.LoadSnapshot(TrackerState{
+		Touches:  []TouchState{{ID: 1, CurrX: 100, CurrY: 100}, {ID: 2, CurrX: 200, CurrY: 100}},
+		Pinch:    &PinchState{ID1: 1, ID2: 3, OriginDistance: 100, Distance: 100, PrevDistance: 100},
+		Pan:      &TwoFingerPanState{ID1: 3, ID2: 4},
+		ThreePan: &ThreeFingerPanState{ID1: 1, ID2: 2, ID3: 3},
+	})
+	if _, ok := tt.Pinch(); ok {
+		t.Error("pinch of a missing touch restored")
+	}
+	if _, ok := tt.TwoFingerPan(); ok {
+		t.Error("pan of missing touches restored")
+	}
+	if _, ok := tt.ThreeFingerPan(); ok {
+		t.Error("three finger pan of a missing touch restored")
+	}
+
+	in.press(1, 100, 100)
+	in.press(2, 200, 100)
+	in.press(3, 300, 100)
+	in.steps(tt, 3)
+}
+
+func TestSubTrackerCoordinates(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		x, y int
+	}{
+		{"relative to the region", nil, 20, 30},
+		{"with its own flip", []Option{WithFlipY(100)}, 20, 70},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt, in := newScripted(WithFlipY(480), WithCoordinateTransform(func(x, y int) (int, int) { return x * 2, y * 2 }))
+			sub := tt.SubTracker(image.Rect(100, 100, 200, 200), test.opts...)
+
+			in.press(1, 50, 50)
+			in.step(sub)
+			in.release(1)
+			in.step(sub)
+			if _, ok := sub.TappedOne(); ok {
+				t.Fatal("tap outside the region reported")
+			}
+
+			in.press(2, 120, 130)
+			in.step(sub)
+			in.release(2)
+			in.step(sub)
+			tap, ok := sub.TappedOne()
+			if !ok {
+				t.Fatal("tap inside the region not reported")
+			}
+			if tap.X != test.x || tap.Y != test.y {
+				t.Errorf("tap at (%d, %d), want (%d, %d)", tap.X, tap.Y, test.x, test.y)
+			}
+		})
+	}
+}
+
+func TestSplitTappedWithFlip(t *testing.T) {
+	tt, in := newScripted(WithFlipX(400))
+	in.press(1, 50, 100)
+	in.press(2, 350, 100)
+	in.step(tt)
+	in.release(1)
+	in.release(2)
+	var split SplitTap
+	ok := false
+	for range 10 {
+		in.step(tt)
+		if split, ok = tt.SplitTapped(SplitVertical, 200, 10); ok {
+			break
+		}
+	}
+	if !ok {
+		t.Fatal("split tap not recognized")
+	}
+	if split.First.X != 50 || split.Second.X != 350 {
+		t.Errorf("first tap at x %d and second at x %d, want 50 and 350", split.First.X, split.Second.X)
+	}
+}
+
+func TestTwoFingerHoldIsAGesture(t *testing.T) {
+	hold := func(tt *TouchTracker, in *scriptedInput) {
+		in.press(1, 100, 100)
+		in.press(2, 200, 100)
+		in.steps(tt, 60)
+	}
+
+	tt, in := newScripted()
+	hold(tt, in)
+	if _, ok := tt.TwoFingerHold(); !ok {
+		t.Fatal("two finger hold not recognized")
+	}
+	if g := tt.CurrentGesture(); g != GestureTwoFingerHold {
+		t.Errorf("current gesture = %v, want GestureTwoFingerHold", g)
+	}
+	tt.Consume(GestureTwoFingerHold)
+	if _, ok := tt.TwoFingerHold(); ok {
+		t.Error("consumed two finger hold reported")
+	}
+
+	tt, in = newScripted()
+	tt.EnableGesture(GestureTwoFingerHold, false)
+	hold(tt, in)
+	if _, ok := tt.TwoFingerHold(); ok {
+		t.Error("disabled two finger hold reported")
+	}
+}
+
+func TestRotatingPinch(t *testing.T) {
+	tt, in := newScripted()
+	in.press(1, 100, 100)
+	in.press(2, 200, 100)
+	in.step(tt)
+	for i := 1; i <= 5; i++ {
+		a, r := 0.1*float64(i), 50+8*float64(i)
+		dx, dy := int(math.Round(r*math.Cos(a))), int(math.Round(r*math.Sin(a)))
+		in.move(1, 150-dx, 100-dy)
+		in.move(2, 150+dx, 100+dy)
+		in.step(tt)
+	}
+
+	if p, ok := tt.Pinch(); !ok || !p.IsRotating() {
+		t.Fatal("expected a rotating pinch")
+	}
+	if g := tt.CurrentGesture(); g != GestureRotate {
+		t.Errorf("current gesture = %v, want GestureRotate", g)
+	}
+	tt.Consume(GestureRotate)
+	if p, ok := tt.Pinch(); !ok || p.IsRotating() {
+		t.Error("expected the pinch to be reported without its consumed rotation")
+	}
+	if g := tt.CurrentGesture(); g != GesturePinch {
+		t.Errorf("current gesture after consuming the rotation = %v, want GesturePinch", g)
+	}
+}
+
+func TestPinchDirectionDeadzone(t *testing.T) {
+	tests := []struct {
+		name            string
+		distance        float64
+		inward, outward bool
+	}{
+		{"still", 100, false, false},
+		{"below the deadzone", 100.5, false, false},
+		{"one pixel out", 101, false, true},
+		{"one pixel in", 99, true, false},
+	}
+	for _, test := range tests {
+		p := Pinch{PrevDistance: 100, Distance: test.distance, deadzone: 1}
+		if p.IsInward() != test.inward || p.IsOutward() != test.outward {
+			t.Errorf("%s: inward %v and outward %v, want %v and %v", test.name, p.IsInward(), p.IsOutward(), test.inward, test.outward)
+		}
+	}
+}
+
+// steadyStates are touch sequences that, once started, repeat the same kind of frame.
+var steadyStates = []struct {
+	name  string
+	start func(in *scriptedInput)
+	frame func(in *scriptedInput, i int)
+}{
+	{"idle", func(in *scriptedInput) {}, func(in *scriptedInput, i int) {}},
+	{"held", func(in *scriptedInput) { in.press(1, 100, 100) }, func(in *scriptedInput, i int) {}},
+	{
+		"dragged",
+		func(in *scriptedInput) { in.press(1, 100, 100) },
+		func(in *scriptedInput, i int) { in.move(1, 100+i%200, 100) },
+	},
+	{
+		"pinched",
+		func(in *scriptedInput) { in.press(1, 100, 100); in.press(2, 200, 100) },
+		func(in *scriptedInput, i int) { in.move(2, 200+i%200, 100) },
+	},
+	{
+		"panned",
+		func(in *scriptedInput) { in.press(1, 100, 100); in.press(2, 200, 100) },
+		func(in *scriptedInput, i int) { in.move(1, 100, 100+i%200); in.move(2, 200, 100+i%200) },
+	},
+}
+
+func TestUpdateDoesNotAllocate(t *testing.T) {
+	for _, state := range steadyStates {
+		t.Run(state.name, func(t *testing.T) {
+			tt, in := newScripted()
+			state.start(in)
+			in.steps(tt, 40)
+			i := 0
+			allocs := testing.AllocsPerRun(100, func() {
+				i++
+				state.frame(in, i)
+				in.step(tt)
+			})
+			if allocs != 0 {
+				t.Errorf("Update allocated %v times per frame, want 0", allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	for _, state := range steadyStates {
+		b.Run(state.name, func(b *testing.B) {
+			tt, in := newScripted()
+			state.start(in)
+			in.steps(tt, 40)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				state.frame(in, i)
+				in.step(tt)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package ebiten_touchutils
+
+// Tracker is the gesture querying interface of TouchTracker, for code that shouldn't depend on
+// the concrete tracker, like game logic shared with a headless server that uses a NopTracker.
+//
+// Building with the touchutils_headless tag leaves out TouchTracker and everything else that
+// depends on ebiten, keeping Tracker, NopTracker and the gesture values, so headless builds
+// don't pull in ebiten's graphics dependencies.
+type Tracker interface {
+	Update()
+	Reset()
+
+	IsTouching() bool
+	TouchCount() int
+	TouchPositions() []TouchPoint
+	CurrentGesture() GestureKind
+
+	Taps() []Tap
+	TappedOne() (Tap, bool)
+	TappedTwo() (Tap, Tap, bool)
+	TappedThree() (Tap, Tap, Tap, bool)
+	DoubleTapped() (Tap, bool)
+	TripleTapped() (Tap, bool)
+	LongPress() (LongPress, bool)
+
+	Pinch() (Pinch, bool)
+	TwoFingerPan() (TwoFingerPan, bool)
+	ThreeFingerPan() (ThreeFingerPan, bool)
+	Swipe() (Swipe, bool)
+	Fling() (Fling, bool)
+	Drag() (Drag, bool)
+}
+
+var _ Tracker = NopTracker{}
+
+// NopTracker is a Tracker that never sees a touch, for builds without touch input.
+type NopTracker struct{}
+
+func (NopTracker) Update() {}
+
+func (NopTracker) Reset() {}
+
+func (NopTracker) IsTouching() bool { return false }
+
+func (NopTracker) TouchCount() int { return 0 }
+
+func (NopTracker) TouchPositions() []TouchPoint { return nil }
+
+func (NopTracker) CurrentGesture() GestureKind { return GestureNone }
+
+func (NopTracker) Taps() []Tap { return nil }
+
+func (NopTracker) TappedOne() (Tap, bool) { return Tap{}, false }
+
+func (NopTracker) TappedTwo() (Tap, Tap, bool) { return Tap{}, Tap{}, false }
+
+func (NopTracker) TappedThree() (Tap, Tap, Tap, bool) { return Tap{}, Tap{}, Tap{}, false }
+
+func (NopTracker) DoubleTapped() (Tap, bool) { return Tap{}, false }
+
+func (NopTracker) TripleTapped() (Tap, bool) { return Tap{}, false }
+
+func (NopTracker) LongPress() (LongPress, bool) { return LongPress{}, false }
+
+func (NopTracker) Pinch() (Pinch, bool) { return Pinch{}, false }
+
+func (NopTracker) TwoFingerPan() (TwoFingerPan, bool) { return TwoFingerPan{}, false }
+
+func (NopTracker) ThreeFingerPan() (ThreeFingerPan, bool) { return ThreeFingerPan{}, false }
+
+func (NopTracker) Swipe() (Swipe, bool) { return Swipe{}, false }
+
+func (NopTracker) Fling() (Fling, bool) { return Fling{}, false }
+
+func (NopTracker) Drag() (Drag, bool) { return Drag{}, false }
@@ -0,0 +1,55 @@
+package ebiten_touchutils
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestNopTracker(t *testing.T) {
+	var tr Tracker = NopTracker{}
+	tr.Update()
+	tr.Reset()
+	if tr.IsTouching() || tr.TouchCount() != 0 || len(tr.TouchPositions()) != 0 || len(tr.Taps()) != 0 {
+		t.Error("NopTracker reports touches")
+	}
+	if g := tr.CurrentGesture(); g != GestureNone {
+		t.Errorf("current gesture = %v, want GestureNone", g)
+	}
+	if _, ok := tr.TappedOne(); ok {
+		t.Error("NopTracker reports a tap")
+	}
+	if _, ok := tr.Pinch(); ok {
+		t.Error("NopTracker reports a pinch")
+	}
+	if _, ok := tr.TwoFingerPan(); ok {
+		t.Error("NopTracker reports a pan")
+	}
+	if _, ok := tr.Drag(); ok {
+		t.Error("NopTracker reports a drag")
+	}
+}
+
+// TestHeadlessBuild checks that the package builds with the touchutils_headless tag without
+// depending on ebiten, so a new file missing the build constraint is caught by the regular tests.
+func TestHeadlessBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the package")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	if out, err := exec.Command(goBin, "build", "-tags", "touchutils_headless", ".").CombinedOutput(); err != nil {
+		t.Fatalf("headless build failed: %v\n%s", err, out)
+	}
+	out, err := exec.Command(goBin, "list", "-deps", "-tags", "touchutils_headless", ".").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dep := range strings.Fields(string(out)) {
+		if strings.HasPrefix(dep, "github.com/hajimehoshi/ebiten") {
+			t.Errorf("headless build depends on %s", dep)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+import "math"
+
+// WithCoordinateTransform sets a function to convert the coordinates reported for gestures, like
+// from screen to world space through a camera. Gestures are still recognized in screen space, so
+// thresholds, distances and velocities stay in screen pixels.
+//
+// The transform applies to the positions of taps, drags, pans, pinch centers, long presses, two
+// finger holds, swipes, flings, flicks and force presses, and to the positions passed to gesture
+// handlers. Raw touch queries like TouchPositions and Touch keep reporting screen coordinates.
+//
+// When combined with WithFlipX or WithFlipY, the transform receives the flipped coordinates.
+func WithCoordinateTransform(f func(x, y int) (int, int)) Option {
+	return func(tt *TouchTracker) {
+		tt.transform = f
+	}
+}
+
+// toWorld applies the axis flips and the coordinate transform to a point, if any.
+func (tt *TouchTracker) toWorld(x, y int) (int, int) {
+	x, y = tt.flip(x, y)
+	if tt.transform == nil {
+		return x, y
+	}
+	return tt.transform(x, y)
+}
+
+// toWorldDelta applies the axis flips and the coordinate transform to a movement of (dx, dy) ending at (x, y).
+func (tt *TouchTracker) toWorldDelta(x, y, dx, dy int) (int, int) {
+	if tt.transform == nil && !tt.flipX && !tt.flipY {
+		return dx, dy
+	}
+	x1, y1 := tt.toWorld(x, y)
+	x0, y0 := tt.toWorld(x-dx, y-dy)
+	return x1 - x0, y1 - y0
+}
+
+func (tt *TouchTracker) worldTap(t Tap) Tap {
+	t.X, t.Y = tt.toWorld(t.X, t.Y)
+	return t
+}
+
+func (tt *TouchTracker) worldDrag(d Drag) Drag {
+	d.DeltaX, d.DeltaY = tt.toWorldDelta(d.CurrX, d.CurrY, d.DeltaX, d.DeltaY)
+	d.StartX, d.StartY = tt.toWorld(d.StartX, d.StartY)
+	d.CurrX, d.CurrY = tt.toWorld(d.CurrX, d.CurrY)
+	return d
+}
+
+func (tt *TouchTracker) worldPan(p TwoFingerPan) TwoFingerPan {
+	p.OriginX, p.OriginY = tt.toWorld(p.OriginX, p.OriginY)
+	p.LastX, p.LastY = tt.toWorld(p.LastX, p.LastY)
+	p.PrevX, p.PrevY = tt.toWorld(p.PrevX, p.PrevY)
+	p.frameSeconds = tt.frameSeconds()
+	return p
+}
+
+func (tt *TouchTracker) worldThreeFingerPan(p ThreeFingerPan) ThreeFingerPan {
+	p.OriginX, p.OriginY = tt.toWorld(p.OriginX, p.OriginY)
+	p.LastX, p.LastY = tt.toWorld(p.LastX, p.LastY)
+	return p
+}
+
+func (tt *TouchTracker) worldPinch(p Pinch) Pinch {
+	// A disabled or consumed rotation isn't reported, while the pinch still is.
+	if tt.handled.has(GestureRotate) {
+		p.rotationThreshold = math.Inf(1)
+	}
+	p.CenterX, p.CenterY = tt.toWorld(p.CenterX, p.CenterY)
+	p.OriginCenterX, p.OriginCenterY = tt.toWorld(p.OriginCenterX, p.OriginCenterY)
+	p.x1, p.y1 = tt.toWorld(p.x1, p.y1)
+	p.x2, p.y2 = tt.toWorld(p.x2, p.y2)
+	return p
+}
+
+func (tt *TouchTracker) worldSwipe(s Swipe) Swipe {
+	s.StartX, s.StartY = tt.toWorld(s.StartX, s.StartY)
+	s.EndX, s.EndY = tt.toWorld(s.EndX, s.EndY)
+	return s
+}
@@ -0,0 +1,343 @@
+package ebiten_touchutils
+
+// The gesture values reported by trackers don't depend on ebiten, so they are also
+// available in headless builds. See Tracker.
+
+import (
+	"math"
+	"time"
+)
+
+// GestureKind identifies a gesture recognized by the TouchTracker.
+type GestureKind int
+
+const (
+	GestureNone GestureKind = iota
+	GestureTap
+	GesturePinch
+	GesturePan
+	GestureForcePress
+	GestureFlick
+	GestureLongPress
+	GestureSwipe
+	GestureDrag
+	GestureThreeFingerPan
+	GestureFling
+	GestureTapDrag
+
+	// GestureTapPending is reported by CurrentGesture while fingers touch the screen that could
+	// still be released as a tap. It's never recognized on touches, so rules on it have no effect.
+	GestureTapPending
+
+	// GestureTwoFingerHold is two fingers held down in place, as reported by TwoFingerHold.
+	GestureTwoFingerHold
+
+	// GestureRotate is a pinch whose fingers rotated around each other past the rotation
+	// threshold, as reported by Pinch.IsRotating. Rotating fingers are still a pinch too.
+	GestureRotate
+)
+
+// TouchPoint is the position of an active touch.
+type TouchPoint struct {
+	ID   TouchID
+	X, Y int
+}
+
+// Tap is the action of pressing and releasing one touch in the screen
+// in a short time and without much movement.
+type Tap struct {
+	X, Y int
+
+	// Duration is the amount of frames the finger was pressed, and Movement how far, in pixels,
+	// it moved from where it was pressed to where it was released. The center of a multi finger
+	// tap has the longest duration and movement of its fingers.
+	Duration int
+	Movement float64
+}
+
+// LongPress is the gesture of holding one finger on the screen without moving it.
+type LongPress struct {
+	X, Y int
+
+	// Duration is for how many frames the finger has been held down.
+	Duration int
+}
+
+// Pinch is the gesture of moving two fingers closer or farther away from each other.
+type Pinch struct {
+	ID1, ID2 TouchID
+
+	OriginDistance float64
+	Distance       float64
+
+	// PrevDistance is the distance between the fingers on the previous update frame,
+	// or the origin distance on the frame the pinch is recognized.
+	PrevDistance float64
+
+	// CenterX, CenterY is the live midpoint between the fingers, updated every update frame,
+	// to zoom around the focus point while the fingers also move across the screen.
+	CenterX, CenterY int
+
+	// OriginCenterX, OriginCenterY is the starting midpoint between the fingers, where they
+	// first touched the screen. It doesn't change while the pinch lasts.
+	OriginCenterX, OriginCenterY int
+
+	// OriginAngle and Angle are the angles in radians, in (-π, π], of the vector from the first to
+	// the second finger where they first touched the screen and on the latest update frame.
+	OriginAngle float64
+	Angle       float64
+
+	// x1, y1 and x2, y2 are the live positions of the first and second finger.
+	x1, y1, x2, y2 int
+
+	deadzone          float64
+	rotationThreshold float64
+}
+
+// Finger1 returns the live position of the first finger, the touch with ID1.
+func (p Pinch) Finger1() (int, int) {
+	return p.x1, p.y1
+}
+
+// Finger2 returns the live position of the second finger, the touch with ID2.
+func (p Pinch) Finger2() (int, int) {
+	return p.x2, p.y2
+}
+
+// IsInward returns if the fingers got closer since the previous update frame by at least the
+// pinch deadzone, set with WithPinchDeadzone.
+func (p Pinch) IsInward() bool {
+	d := p.PrevDistance - p.Distance
+	return d > 0 && d >= p.deadzone
+}
+
+// IsOutward returns if the fingers got farther apart since the previous update frame by at least
+// the pinch deadzone, set with WithPinchDeadzone.
+func (p Pinch) IsOutward() bool {
+	d := p.Distance - p.PrevDistance
+	return d > 0 && d >= p.deadzone
+}
+
+// Scale returns the ratio between the current and the origin distance between the fingers,
+// greater than 1 when spreading them and lower than 1 when closing them.
+//
+// Returns 1 if the origin distance is 0.
+func (p Pinch) Scale() float64 {
+	if p.OriginDistance == 0 {
+		return 1
+	}
+	return p.Distance / p.OriginDistance
+}
+
+// ScaleDelta returns the ratio between the current distance between the fingers and the one
+// on the previous update frame, to apply incremental zoom every frame. Multiplying every
+// ScaleDelta since the pinch was recognized gives Scale.
+//
+// Returns 1 if the previous distance is 0.
+func (p Pinch) ScaleDelta() float64 {
+	if p.PrevDistance == 0 {
+		return 1
+	}
+	return p.Distance / p.PrevDistance
+}
+
+// RotationDelta returns how much the fingers rotated around each other since they first touched
+// the screen, in radians within (-π, π]. Positive values are clockwise on screen.
+func (p Pinch) RotationDelta() float64 {
+	return normalizeAngle(p.Angle - p.OriginAngle)
+}
+
+// IsRotating returns if the fingers rotated around each other since they first touched the screen
+// by more than the rotation threshold, set with WithRotationThreshold.
+func (p Pinch) IsRotating() bool {
+	return math.Abs(p.RotationDelta()) > p.rotationThreshold
+}
+
+// pinchDiagonalRatio is the ratio between the shorter and the longer of the horizontal and vertical
+// distances between the fingers of a pinch above which it's diagonal, that of 30 degrees off an axis.
+const pinchDiagonalRatio = 0.577
+
+// IsHorizontal returns if the fingers are side by side, so the pinch spreads or closes along
+// the horizontal axis, within 30 degrees of it.
+func (p Pinch) IsHorizontal() bool {
+	dx, dy := math.Abs(float64(p.x2-p.x1)), math.Abs(float64(p.y2-p.y1))
+	return dx > dy && dy/dx <= pinchDiagonalRatio
+}
+
+// IsVertical returns if the fingers are one above the other, so the pinch spreads or closes along
+// the vertical axis, within 30 degrees of it.
+func (p Pinch) IsVertical() bool {
+	dx, dy := math.Abs(float64(p.x2-p.x1)), math.Abs(float64(p.y2-p.y1))
+	return dy > dx && dx/dy <= pinchDiagonalRatio
+}
+
+// IsDiagonal returns if the pinch is neither horizontal nor vertical, with the fingers
+// lined up closer to a diagonal than to either axis.
+func (p Pinch) IsDiagonal() bool {
+	return !p.IsHorizontal() && !p.IsVertical()
+}
+
+// normalizeAngle wraps an angle in radians into (-π, π], so rotations crossing the
+// -π/π boundary don't become a full turn.
+func normalizeAngle(a float64) float64 {
+	a = math.Mod(a, 2*math.Pi)
+	if a <= -math.Pi {
+		a += 2 * math.Pi
+	} else if a > math.Pi {
+		a -= 2 * math.Pi
+	}
+	return a
+}
+
+// TwoFingerPan is the gesture of moving two fingers across the screen
+// either vertically or horizontally, without much change in the distance between the fingers.
+//
+// Positions are the midpoint between both fingers, so the pan stays stable when
+// they move asymmetrically.
+type TwoFingerPan struct {
+	ID1, ID2 TouchID
+
+	LastX, LastY     int
+	OriginX, OriginY int
+
+	// PrevX, PrevY is the position on the previous update frame.
+	PrevX, PrevY int
+
+	isHorizontal bool
+	threshold    float64
+	coasting     bool
+	frameSeconds float64
+
+	// polledX, polledY is the position as of the last ConsumePanDelta call.
+	polledX, polledY int
+}
+
+// FrameDelta returns the movement of the pan since the previous update frame, in pixels.
+// On the frame the pan is recognized it's the movement made in that frame, so the pan follows
+// the fingers smoothly from the start.
+func (p TwoFingerPan) FrameDelta() (int, int) {
+	return p.LastX - p.PrevX, p.LastY - p.PrevY
+}
+
+// Velocity returns the speed of the pan in pixels per frame, as of the last update frame.
+// On the frame the pan is recognized it's the speed of the fingers, rather than a spike from the origin.
+func (p TwoFingerPan) Velocity() (float64, float64) {
+	dx, dy := p.FrameDelta()
+	return float64(dx), float64(dy)
+}
+
+// VelocityPerSecond returns the speed of the pan in pixels per second, as of the last update frame,
+// by the time the frame lasted, so it doesn't depend on the frame rate. See WithTimeSource.
+func (p TwoFingerPan) VelocityPerSecond() (float64, float64) {
+	vx, vy := p.Velocity()
+	if p.frameSeconds <= 0 {
+		return vx / defaultFrameTime.Seconds(), vy / defaultFrameTime.Seconds()
+	}
+	return vx / p.frameSeconds, vy / p.frameSeconds
+}
+
+// Direction returns the dominant direction of the pan from its origin to its last position.
+// It returns DirNone if the pan moved back within the pan threshold of its origin.
+func (p TwoFingerPan) Direction() Direction {
+	dx, dy := float64(p.LastX-p.OriginX), float64(p.LastY-p.OriginY)
+	if math.Abs(dx) <= p.threshold && math.Abs(dy) <= p.threshold {
+		return DirNone
+	}
+	return directionOf(dx, dy)
+}
+
+// Angle returns the direction of travel of the pan from its origin to its last position, in radians
+// within (-π, π]. Zero points right, and positive values are clockwise on screen. It returns 0 if the
+// pan is back at its origin.
+func (p TwoFingerPan) Angle() float64 {
+	dx, dy := p.LastX-p.OriginX, p.LastY-p.OriginY
+	if dx == 0 && dy == 0 {
+		return 0
+	}
+	return math.Atan2(float64(dy), float64(dx))
+}
+
+// IsCoasting returns if the fingers were released and the pan is gliding on its momentum,
+// as enabled by WithMomentum.
+func (p TwoFingerPan) IsCoasting() bool {
+	return p.coasting
+}
+
+func (p TwoFingerPan) IsHorizontal() bool {
+	return p.isHorizontal
+}
+
+func (p TwoFingerPan) IsVertical() bool {
+	return !p.isHorizontal
+}
+
+// ThreeFingerPan is the gesture of moving three fingers together across the screen
+// either vertically or horizontally.
+//
+// Positions are the centroid of the three fingers.
+type ThreeFingerPan struct {
+	ID1, ID2, ID3 TouchID
+
+	LastX, LastY     int
+	OriginX, OriginY int
+
+	isHorizontal bool
+}
+
+func (p ThreeFingerPan) IsHorizontal() bool {
+	return p.isHorizontal
+}
+
+func (p ThreeFingerPan) IsVertical() bool {
+	return !p.isHorizontal
+}
+
+// Drag is the gesture of moving one finger across the screen while keeping it pressed.
+type Drag struct {
+	StartX, StartY int
+	CurrX, CurrY   int
+
+	// DeltaX, DeltaY is the movement of the finger since the previous update frame.
+	DeltaX, DeltaY int
+
+	// VelocityX, VelocityY is the smoothed speed of the finger, in pixels per frame,
+	// as set by WithVelocitySmoothing, and VelocityPerSecondX, VelocityPerSecondY the same
+	// in pixels per second, by the time the last update frame lasted. See WithTimeSource.
+	VelocityX, VelocityY                   float64
+	VelocityPerSecondX, VelocityPerSecondY float64
+}
+
+// Swipe is the gesture of quickly moving one finger across the screen and releasing it.
+type Swipe struct {
+	StartX, StartY int
+	EndX, EndY     int
+
+	Direction Direction
+
+	// VelocityX, VelocityY is the average speed of the finger, in pixels per frame, and
+	// VelocityPerSecondX, VelocityPerSecondY in pixels per second, which doesn't depend on the
+	// frame rate. See WithTimeSource.
+	VelocityX, VelocityY                   float64
+	VelocityPerSecondX, VelocityPerSecondY float64
+}
+
+// Fling is the gesture of throwing one finger across the screen and releasing it while it's fast.
+//
+// Unlike a swipe, which is recognized by distance, a fling is recognized by speed, so a long and
+// slow drag across the whole screen is not a fling.
+type Fling struct {
+	StartX, StartY int
+	EndX, EndY     int
+
+	// VelocityX, VelocityY is the average speed of the finger, in pixels per frame,
+	// from its total displacement over the frames it was pressed, and VelocityPerSecondX,
+	// VelocityPerSecondY the same in pixels per second, over the time it was pressed.
+	VelocityX, VelocityY                   float64
+	VelocityPerSecondX, VelocityPerSecondY float64
+
+	Direction Direction
+}
+
+// defaultFrameTime is the duration assumed for an update frame until one has been measured,
+// that of ebiten's default 60 TPS.
+const defaultFrameTime = time.Second / 60
@@ -0,0 +1,69 @@
+//go:build !touchutils_headless
+
+package ebiten_touchutils
+
+// maxVelocitySamples is the capacity of the per touch velocity history.
+const maxVelocitySamples = 16
+
+// velocitySample is the movement of a touch in a single frame, in pixels.
+type velocitySample struct {
+	x, y float64
+}
+
+// velocityRing keeps the latest per frame velocities of a touch.
+type velocityRing struct {
+	samples [maxVelocitySamples]velocitySample
+	next, n int
+}
+
+func (r *velocityRing) push(x, y float64) {
+	r.samples[r.next] = velocitySample{x, y}
+	r.next = (r.next + 1) % maxVelocitySamples
+	if r.n < maxVelocitySamples {
+		r.n++
+	}
+}
+
+// at returns the i-th latest sample, 0 being the newest one.
+func (r *velocityRing) at(i int) velocitySample {
+	return r.samples[(r.next-1-i+maxVelocitySamples)%maxVelocitySamples]
+}
+
+// smoothed returns the weighted average of the last window samples.
+//
+// Weights are triangular over the window, so the samples in the middle of it count
+// the most and the last frame before a release, which often decelerates or jitters,
+// counts the least.
+func (r *velocityRing) smoothed(window int) (float64, float64, bool) {
+	window = min(window, r.n)
+	if window <= 0 {
+		return 0, 0, false
+	}
+	var x, y, total float64
+	for i := 0; i < window; i++ {
+		w := float64(min(i+1, window-i))
+		s := r.at(i)
+		x += s.x * w
+		y += s.y * w
+		total += w
+	}
+	return x / total, y / total, true
+}
+
+// WithFlickSampleWindow sets how many of the latest frames are averaged to find the
+// direction of a flick on release. Defaults to 5, and is capped at 16.
+func WithFlickSampleWindow(frames int) Option {
+	return func(tt *TouchTracker) {
+		tt.flickSampleWindow = max(1, min(frames, maxVelocitySamples))
+	}
+}
+
+// WithVelocitySmoothing sets the weight, in (0, 1], of the latest frame in the velocity reported
+// for each touch, which is an exponential moving average of its per frame movement. Lower values
+// smooth out jitter but react slower, and 1 reports the movement of the last frame alone.
+// Defaults to 0.5.
+func WithVelocitySmoothing(alpha float64) Option {
+	return func(tt *TouchTracker) {
+		tt.velocitySmoothing = max(0.01, min(alpha, 1))
+	}
+}